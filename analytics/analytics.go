@@ -0,0 +1,126 @@
+// gammago/analytics/analytics.go
+
+// Package analytics turns Gamma outcome prices into implied probabilities
+//
+// Prices are read from Market.OutcomePrices, so they are the last traded or
+// mid prices Gamma reports, not executable quotes. A market's prices rarely
+// sum to exactly 1; the difference is reported as overround and the
+// probabilities are normalized to sum to 1
+package analytics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+var (
+	// ErrNoPrices is returned when a market has no usable outcome prices
+	ErrNoPrices = errors.New("analytics: no outcome prices")
+
+	// ErrZeroTotal is returned when every price is zero, so nothing can be normalized
+	ErrZeroTotal = errors.New("analytics: outcome prices sum to zero")
+
+	// ErrNotNegRisk is returned by NormalizeNegRisk for events without NegRisk set
+	ErrNotNegRisk = errors.New("analytics: event is not neg-risk")
+)
+
+// MissingPriceError reports a market whose price couldn't be read
+type MissingPriceError struct {
+	MarketID string
+	Err      error
+}
+
+func (e *MissingPriceError) Error() string {
+	return fmt.Sprintf("analytics: market %s: %v", e.MarketID, e.Err)
+}
+
+func (e *MissingPriceError) Unwrap() error { return e.Err }
+
+// Outcome is one outcome of a market
+// Probability is Price divided by the sum of the market's prices
+type Outcome struct {
+	Name        string
+	Price       float64
+	Probability float64
+}
+
+// MarketProbabilities is the implied probability of each outcome of a market
+// Overround is Sum - 1: positive when prices over-sum, negative when they under-sum
+type MarketProbabilities struct {
+	MarketID  string
+	Outcomes  []Outcome
+	Sum       float64
+	Overround float64
+}
+
+// Implied computes per-outcome implied probabilities for a market
+// A price that is missing or unparseable is an error; a zero price is
+// kept and gets probability 0
+func Implied(m gamma.Market) (MarketProbabilities, error) {
+	names, prices, err := outcomePrices(m)
+	if err != nil {
+		return MarketProbabilities{}, &MissingPriceError{MarketID: m.ID, Err: err}
+	}
+
+	mp := MarketProbabilities{MarketID: m.ID, Outcomes: make([]Outcome, len(prices))}
+	for i, p := range prices {
+		mp.Outcomes[i] = Outcome{Name: names[i], Price: p}
+		mp.Sum += p
+	}
+	if mp.Sum == 0 {
+		return mp, &MissingPriceError{MarketID: m.ID, Err: ErrZeroTotal}
+	}
+	for i := range mp.Outcomes {
+		mp.Outcomes[i].Probability = mp.Outcomes[i].Price / mp.Sum
+	}
+	mp.Overround = mp.Sum - 1
+	return mp, nil
+}
+
+// YesPrice is the price of the market's "Yes" outcome
+// Markets without a Yes outcome use the first outcome, which is how Gamma
+// orders binary markets
+func YesPrice(m gamma.Market) (float64, error) {
+	names, prices, err := outcomePrices(m)
+	if err != nil {
+		return 0, &MissingPriceError{MarketID: m.ID, Err: err}
+	}
+	for i, n := range names {
+		if strings.EqualFold(n, "Yes") {
+			return prices[i], nil
+		}
+	}
+	return prices[0], nil
+}
+
+// outcomePrices decodes Outcomes and OutcomePrices and checks they line up
+// Outcome names are optional; a missing name is left empty
+func outcomePrices(m gamma.Market) ([]string, []float64, error) {
+	var raw []string
+	if m.OutcomePrices == "" || json.Unmarshal([]byte(m.OutcomePrices), &raw) != nil || len(raw) == 0 {
+		return nil, nil, ErrNoPrices
+	}
+
+	prices := make([]float64, len(raw))
+	for i, s := range raw {
+		p, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: price %q", ErrNoPrices, s)
+		}
+		if p < 0 || p > 1 {
+			return nil, nil, fmt.Errorf("%w: price %v out of range", ErrNoPrices, p)
+		}
+		prices[i] = p
+	}
+
+	var names []string
+	if json.Unmarshal([]byte(m.Outcomes), &names) != nil || len(names) != len(prices) {
+		names = make([]string, len(prices))
+	}
+	return names, prices, nil
+}
@@ -0,0 +1,137 @@
+// gammago/analytics/analytics_test.go
+
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestImplied(t *testing.T) {
+	t.Run("binary market with overround", func(t *testing.T) {
+		m := gamma.Market{ID: "1", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.55", "0.50"]`}
+		mp, err := Implied(m)
+		if err != nil {
+			t.Fatalf("Implied failed: %v", err)
+		}
+		if !near(mp.Sum, 1.05) || !near(mp.Overround, 0.05) {
+			t.Errorf("Expected sum 1.05 and overround 0.05, got %v and %v", mp.Sum, mp.Overround)
+		}
+		if mp.Outcomes[0].Name != "Yes" || !near(mp.Outcomes[0].Probability, 0.55/1.05) {
+			t.Errorf("Unexpected Yes outcome %+v", mp.Outcomes[0])
+		}
+		if !near(mp.Outcomes[0].Probability+mp.Outcomes[1].Probability, 1) {
+			t.Errorf("Expected probabilities to sum to 1")
+		}
+	})
+
+	t.Run("zero price is kept", func(t *testing.T) {
+		mp, err := Implied(gamma.Market{ID: "2", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0", "1"]`})
+		if err != nil {
+			t.Fatalf("Implied failed: %v", err)
+		}
+		if mp.Outcomes[0].Probability != 0 || mp.Outcomes[1].Probability != 1 {
+			t.Errorf("Unexpected outcomes %+v", mp.Outcomes)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		prices string
+		want   error
+	}{
+		{"empty", "", ErrNoPrices},
+		{"not json", "nope", ErrNoPrices},
+		{"empty list", "[]", ErrNoPrices},
+		{"unparseable", `["0.5", "abc"]`, ErrNoPrices},
+		{"out of range", `["1.5", "0"]`, ErrNoPrices},
+		{"all zero", `["0", "0"]`, ErrZeroTotal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Implied(gamma.Market{ID: "3", OutcomePrices: tt.prices})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+			var mpe *MissingPriceError
+			if !errors.As(err, &mpe) || mpe.MarketID != "3" {
+				t.Errorf("Expected MissingPriceError for market 3, got %v", err)
+			}
+		})
+	}
+}
+
+func TestYesPrice(t *testing.T) {
+	p, err := YesPrice(gamma.Market{Outcomes: `["No", "Yes"]`, OutcomePrices: `["0.3", "0.7"]`})
+	if err != nil || p != 0.7 {
+		t.Errorf("Expected 0.7, got %v %v", p, err)
+	}
+	p, err = YesPrice(gamma.Market{Outcomes: `["Lakers", "Celtics"]`, OutcomePrices: `["0.45", "0.55"]`})
+	if err != nil || p != 0.45 {
+		t.Errorf("Expected first outcome 0.45, got %v %v", p, err)
+	}
+}
+
+func TestNormalizeNegRisk(t *testing.T) {
+	event := gamma.Event{
+		ID:      "100",
+		NegRisk: true,
+		Markets: []gamma.Market{
+			{ID: "1", Question: "A wins?", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.50", "0.50"]`},
+			{ID: "2", Question: "B wins?", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.30", "0.70"]`},
+			{ID: "3", Question: "C wins?", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.30", "0.70"]`},
+			{ID: "4", Question: "Other?", Outcomes: `["Yes", "No"]`},
+		},
+	}
+
+	t.Run("exclude missing", func(t *testing.T) {
+		ep, err := NormalizeNegRisk(event, ExcludeMissing)
+		if err != nil {
+			t.Fatalf("NormalizeNegRisk failed: %v", err)
+		}
+		if !near(ep.Sum, 1.1) || !near(ep.Overround, 0.1) {
+			t.Errorf("Expected sum 1.1 and overround 0.1, got %v and %v", ep.Sum, ep.Overround)
+		}
+		if len(ep.Missing) != 1 || ep.Missing[0] != "4" || !ep.Markets[3].Missing {
+			t.Errorf("Expected market 4 missing, got %v", ep.Missing)
+		}
+		var total float64
+		for _, m := range ep.Markets {
+			total += m.Probability
+		}
+		if !near(total, 1) {
+			t.Errorf("Expected probabilities to sum to 1, got %v", total)
+		}
+		if !near(ep.Markets[0].Probability, 0.5/1.1) || ep.Markets[0].Question != "A wins?" {
+			t.Errorf("Unexpected first market %+v", ep.Markets[0])
+		}
+	})
+
+	t.Run("fail on missing", func(t *testing.T) {
+		_, err := NormalizeNegRisk(event, FailOnMissing)
+		var mpe *MissingPriceError
+		if !errors.As(err, &mpe) || mpe.MarketID != "4" || !errors.Is(err, ErrNoPrices) {
+			t.Errorf("Expected missing price on market 4, got %v", err)
+		}
+	})
+
+	t.Run("not neg-risk", func(t *testing.T) {
+		e := event
+		e.NegRisk = false
+		if _, err := NormalizeNegRisk(e, ExcludeMissing); !errors.Is(err, ErrNotNegRisk) {
+			t.Errorf("Expected ErrNotNegRisk, got %v", err)
+		}
+	})
+
+	t.Run("nothing priced", func(t *testing.T) {
+		e := gamma.Event{NegRisk: true, Markets: []gamma.Market{{ID: "1"}}}
+		if _, err := NormalizeNegRisk(e, ExcludeMissing); !errors.Is(err, ErrZeroTotal) {
+			t.Errorf("Expected ErrZeroTotal, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+// gammago/analytics/negrisk.go
+
+package analytics
+
+import (
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// MissingPolicy decides what NormalizeNegRisk does with markets whose Yes
+// price can't be read
+type MissingPolicy int
+
+const (
+	// ExcludeMissing leaves the market out of the sum and lists it in Missing
+	ExcludeMissing MissingPolicy = iota
+	// FailOnMissing returns a *MissingPriceError for the first such market
+	FailOnMissing
+)
+
+// MarketProbability is one market's share of a neg-risk event
+// Missing markets have zero Price and Probability
+type MarketProbability struct {
+	MarketID    string
+	Question    string
+	Price       float64
+	Probability float64
+	Missing     bool
+}
+
+// EventProbabilities is a neg-risk event's markets normalized to sum to 1
+// Sum is the total Yes price of the priced markets; Overround is Sum - 1
+type EventProbabilities struct {
+	EventID   string
+	Markets   []MarketProbability
+	Sum       float64
+	Overround float64
+	Missing   []string
+}
+
+// NormalizeNegRisk treats the Yes prices of a neg-risk event's markets as
+// one distribution over mutually exclusive outcomes and normalizes it
+// Markets keep their order in Event.Markets
+func NormalizeNegRisk(e gamma.Event, missing MissingPolicy) (EventProbabilities, error) {
+	if !e.NegRisk {
+		return EventProbabilities{}, ErrNotNegRisk
+	}
+
+	ep := EventProbabilities{EventID: e.ID, Markets: make([]MarketProbability, len(e.Markets))}
+	for i, m := range e.Markets {
+		mp := MarketProbability{MarketID: m.ID, Question: m.Question}
+		p, err := YesPrice(m)
+		if err != nil {
+			if missing == FailOnMissing {
+				return EventProbabilities{}, err
+			}
+			mp.Missing = true
+			ep.Missing = append(ep.Missing, m.ID)
+		} else {
+			mp.Price = p
+			ep.Sum += p
+		}
+		ep.Markets[i] = mp
+	}
+
+	if ep.Sum == 0 {
+		return ep, ErrZeroTotal
+	}
+	for i := range ep.Markets {
+		ep.Markets[i].Probability = ep.Markets[i].Price / ep.Sum
+	}
+	ep.Overround = ep.Sum - 1
+	return ep, nil
+}
+
+// Overround is the sum of a market's outcome prices minus 1
+func Overround(m gamma.Market) (float64, error) {
+	mp, err := Implied(m)
+	return mp.Overround, err
+}
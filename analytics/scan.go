@@ -0,0 +1,284 @@
+// gammago/analytics/scan.go
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// FindingKind is the kind of inconsistency a scan flagged
+type FindingKind int
+
+const (
+	// NegRiskUnder is a neg-risk event whose Yes prices sum below 1
+	NegRiskUnder FindingKind = iota + 1
+	// NegRiskOver is a neg-risk event whose Yes prices sum above 1
+	NegRiskOver
+	// BinaryMispriced is a two-outcome market whose prices are off 1 by more than its spread
+	BinaryMispriced
+	// DuplicateQuestion is the same question asked in more than one event
+	DuplicateQuestion
+)
+
+func (k FindingKind) String() string {
+	switch k {
+	case NegRiskUnder:
+		return "neg-risk under"
+	case NegRiskOver:
+		return "neg-risk over"
+	case BinaryMispriced:
+		return "binary mispriced"
+	case DuplicateQuestion:
+		return "duplicate question"
+	}
+	return "unknown"
+}
+
+// Finding is one flagged event, market or group of markets
+// Deviation is what the report is ranked by:
+//   - neg-risk: |sum of Yes prices - 1|
+//   - binary: |Yes + No - 1| beyond the market's spread
+//   - duplicates: the gap between the highest and lowest Yes price
+type Finding struct {
+	Kind      FindingKind
+	EventIDs  []string
+	MarketIDs []string
+	Question  string
+	Sum       float64
+	Deviation float64
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%-18s  dev %.4f  sum %.4f  events %s  markets %s  %s",
+		f.Kind, f.Deviation, f.Sum, strings.Join(f.EventIDs, ","), strings.Join(f.MarketIDs, ","), f.Question)
+}
+
+// ScanOptions sets the thresholds for Scan
+type ScanOptions struct {
+	// NegRiskThreshold is how far a neg-risk event's Yes prices may sum
+	// from 1 before it is flagged. Defaults to DefaultNegRiskThreshold
+	NegRiskThreshold float64
+
+	// BinarySlack is added to each binary market's Spread before flagging
+	BinarySlack float64
+
+	// IncludeInactive scans inactive markets and events too
+	IncludeInactive bool
+}
+
+// DefaultNegRiskThreshold is used when ScanOptions.NegRiskThreshold is 0
+const DefaultNegRiskThreshold = 0.02
+
+// Report is the ranked result of a scan, largest deviation first
+type Report struct {
+	Findings []Finding
+	Events   int
+	Markets  int
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Report{Events: %d, Markets: %d, Findings: %d}\n", r.Events, r.Markets, len(r.Findings))
+	for i, f := range r.Findings {
+		fmt.Fprintf(&b, "  %3d. %s\n", i+1, f)
+	}
+	return b.String()
+}
+
+// Scan checks events for neg-risk sums away from 1, binary markets that
+// don't add up and questions duplicated across events
+// Markets without readable prices are skipped
+func Scan(events []gamma.Event, opts ScanOptions) Report {
+	threshold := opts.NegRiskThreshold
+	if threshold <= 0 {
+		threshold = DefaultNegRiskThreshold
+	}
+
+	var r Report
+	byQuestion := make(map[string][]questionMarket)
+	negRiskSeen := make(map[string]bool)
+
+	for _, e := range events {
+		if !e.Active && !opts.IncludeInactive {
+			continue
+		}
+		r.Events++
+
+		// the same neg-risk group can come back on several pages or events
+		if e.NegRisk && !negRiskSeen[e.NegRiskMarketID] {
+			if e.NegRiskMarketID != "" {
+				negRiskSeen[e.NegRiskMarketID] = true
+			}
+			if f, ok := scanNegRisk(e, threshold, opts.IncludeInactive); ok {
+				r.Findings = append(r.Findings, f)
+			}
+		}
+
+		for _, m := range e.Markets {
+			if !m.Active && !opts.IncludeInactive {
+				continue
+			}
+			r.Markets++
+
+			if f, ok := scanBinary(e, m, opts.BinarySlack); ok {
+				r.Findings = append(r.Findings, f)
+			}
+			if q := normalizeQuestion(m.Question); q != "" {
+				byQuestion[q] = append(byQuestion[q], questionMarket{e.ID, m})
+			}
+		}
+	}
+
+	r.Findings = append(r.Findings, duplicates(byQuestion)...)
+
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		return r.Findings[i].Deviation > r.Findings[j].Deviation
+	})
+	return r
+}
+
+// EventPager gets one page of events
+type EventPager func(limit, offset int) ([]gamma.Event, error)
+
+// ActiveEventsBetween pages active events ending between start and end,
+// highest volume first
+func ActiveEventsBetween(start, end time.Time) EventPager {
+	return func(limit, offset int) ([]gamma.Event, error) {
+		return gamma.GetEventsBetweenDates(limit, offset, 0, 0, end, start, gamma.ACTIVE)
+	}
+}
+
+// ScanPages fetches pages of events until a short page or maxPages
+// (0 for no limit) and scans them together, so duplicates across pages
+// are found
+func ScanPages(ctx context.Context, pager EventPager, limit, maxPages int, opts ScanOptions) (Report, error) {
+	var events []gamma.Event
+	for page := 0; maxPages == 0 || page < maxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return Report{}, err
+		}
+		batch, err := pager(limit, page*limit)
+		if err != nil {
+			return Report{}, err
+		}
+		events = append(events, batch...)
+		if len(batch) < limit {
+			break
+		}
+	}
+	return Scan(events, opts), nil
+}
+
+// scanNegRisk sums the Yes prices of one neg-risk group
+// The group is the event's NegRiskMarketID: markets with a different
+// NegRiskMarketID belong to another group and are left out of the sum
+// Events without the ID are treated as a group of their own
+func scanNegRisk(e gamma.Event, threshold float64, includeInactive bool) (Finding, bool) {
+	var group []gamma.Market
+	for _, m := range e.Markets {
+		if !m.Active && !includeInactive {
+			continue
+		}
+		if e.NegRiskMarketID != "" && m.NegRiskMarketID != "" && m.NegRiskMarketID != e.NegRiskMarketID {
+			continue
+		}
+		group = append(group, m)
+	}
+	e.Markets = group
+
+	ep, err := NormalizeNegRisk(e, ExcludeMissing)
+	if err != nil {
+		return Finding{}, false
+	}
+	dev := math.Abs(ep.Overround)
+	if dev <= threshold {
+		return Finding{}, false
+	}
+
+	f := Finding{Kind: NegRiskOver, EventIDs: []string{e.ID}, Question: e.Title, Sum: ep.Sum, Deviation: dev}
+	if ep.Overround < 0 {
+		f.Kind = NegRiskUnder
+	}
+	for _, m := range ep.Markets {
+		if !m.Missing {
+			f.MarketIDs = append(f.MarketIDs, m.MarketID)
+		}
+	}
+	return f, true
+}
+
+func scanBinary(e gamma.Event, m gamma.Market, slack float64) (Finding, bool) {
+	mp, err := Implied(m)
+	if err != nil || len(mp.Outcomes) != 2 {
+		return Finding{}, false
+	}
+	dev := math.Abs(mp.Overround) - m.Spread - slack
+	if dev <= 1e-9 {
+		return Finding{}, false
+	}
+	return Finding{
+		Kind:      BinaryMispriced,
+		EventIDs:  []string{e.ID},
+		MarketIDs: []string{m.ID},
+		Question:  m.Question,
+		Sum:       mp.Sum,
+		Deviation: dev,
+	}, true
+}
+
+type questionMarket struct {
+	eventID string
+	market  gamma.Market
+}
+
+// duplicates flags questions asked in more than one event, in question order
+func duplicates(byQuestion map[string][]questionMarket) []Finding {
+	questions := make([]string, 0, len(byQuestion))
+	for q := range byQuestion {
+		questions = append(questions, q)
+	}
+	sort.Strings(questions)
+
+	var findings []Finding
+	for _, q := range questions {
+		group := byQuestion[q]
+		events := map[string]bool{}
+		for _, qm := range group {
+			events[qm.eventID] = true
+		}
+		if len(events) < 2 {
+			continue
+		}
+
+		f := Finding{Kind: DuplicateQuestion, Question: group[0].market.Question}
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, qm := range group {
+			if !slices.Contains(f.EventIDs, qm.eventID) {
+				f.EventIDs = append(f.EventIDs, qm.eventID)
+			}
+			f.MarketIDs = append(f.MarketIDs, qm.market.ID)
+			if p, err := YesPrice(qm.market); err == nil {
+				lo, hi = math.Min(lo, p), math.Max(hi, p)
+			}
+		}
+		if hi >= lo {
+			f.Deviation = hi - lo
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// normalizeQuestion lowercases, collapses whitespace and drops a trailing "?"
+func normalizeQuestion(q string) string {
+	q = strings.ToLower(strings.Join(strings.Fields(q), " "))
+	return strings.TrimSpace(strings.TrimSuffix(q, "?"))
+}
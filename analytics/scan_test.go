@@ -0,0 +1,160 @@
+// gammago/analytics/scan_test.go
+
+package analytics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func binary(id, question, yes, no string, spread float64) gamma.Market {
+	return gamma.Market{
+		ID:            id,
+		Question:      question,
+		Active:        true,
+		Outcomes:      `["Yes", "No"]`,
+		OutcomePrices: `["` + yes + `", "` + no + `"]`,
+		Spread:        spread,
+	}
+}
+
+var scanEvents = []gamma.Event{
+	{
+		ID: "1", Title: "Who wins the election?", Active: true, NegRisk: true,
+		Markets: []gamma.Market{
+			binary("11", "Will A win the election?", "0.40", "0.60", 0.01),
+			binary("12", "Will B win the election?", "0.30", "0.70", 0.01),
+			binary("13", "Will C win the election?", "0.20", "0.80", 0.01),
+		}, // sums to 0.90
+	},
+	{
+		ID: "2", Title: "Who wins the cup?", Active: true, NegRisk: true,
+		Markets: []gamma.Market{
+			binary("21", "Will X win the cup?", "0.50", "0.50", 0.01),
+			binary("22", "Will Y win the cup?", "0.51", "0.49", 0.01),
+		}, // sums to 1.01, inside the default threshold
+	},
+	{
+		ID: "3", Title: "Rain tomorrow?", Active: true,
+		Markets: []gamma.Market{
+			binary("31", "Will it rain tomorrow?", "0.60", "0.46", 0.02), // off by 0.06, spread 0.02
+			binary("32", "Will it snow tomorrow?", "0.10", "0.91", 0.02), // off by 0.01, inside spread
+		},
+	},
+	{
+		ID: "4", Title: "Weather", Active: true,
+		Markets: []gamma.Market{
+			binary("41", "will it rain  tomorrow", "0.45", "0.55", 0.01),
+		},
+	},
+	{
+		ID: "5", Title: "Old", Active: false, NegRisk: true,
+		Markets: []gamma.Market{binary("51", "Closed?", "0.1", "0.1", 0)},
+	},
+}
+
+func TestScan(t *testing.T) {
+	r := Scan(scanEvents, ScanOptions{})
+
+	if r.Events != 4 || r.Markets != 8 {
+		t.Errorf("Expected 4 events and 8 markets scanned, got %d and %d", r.Events, r.Markets)
+	}
+
+	var kinds []string
+	for _, f := range r.Findings {
+		kinds = append(kinds, f.Kind.String())
+	}
+	// ranked: duplicate gap 0.15, neg-risk 0.10, binary 0.04
+	want := "duplicate question,neg-risk under,binary mispriced"
+	if strings.Join(kinds, ",") != want {
+		t.Fatalf("Expected %s, got %s", want, strings.Join(kinds, ","))
+	}
+
+	dup := r.Findings[0]
+	if !near(dup.Deviation, 0.15) || strings.Join(dup.EventIDs, ",") != "3,4" || strings.Join(dup.MarketIDs, ",") != "31,41" {
+		t.Errorf("Unexpected duplicate finding %+v", dup)
+	}
+
+	neg := r.Findings[1]
+	if !near(neg.Sum, 0.9) || !near(neg.Deviation, 0.1) || neg.EventIDs[0] != "1" || len(neg.MarketIDs) != 3 {
+		t.Errorf("Unexpected neg-risk finding %+v", neg)
+	}
+
+	bin := r.Findings[2]
+	if bin.MarketIDs[0] != "31" || !near(bin.Deviation, 0.04) {
+		t.Errorf("Unexpected binary finding %+v", bin)
+	}
+}
+
+func TestScanNegRiskMarketID(t *testing.T) {
+	tagged := func(m gamma.Market, group string) gamma.Market {
+		m.NegRiskMarketID = group
+		return m
+	}
+	events := []gamma.Event{
+		{
+			ID: "1", Title: "Who wins?", Active: true, NegRisk: true, NegRiskMarketID: "0xaa",
+			Markets: []gamma.Market{
+				tagged(binary("11", "A?", "0.60", "0.40", 0), "0xaa"),
+				tagged(binary("12", "B?", "0.40", "0.60", 0), "0xaa"),
+				tagged(binary("13", "Other group?", "0.30", "0.70", 0), "0xbb"), // not part of 0xaa
+			},
+		},
+		{
+			// the same group again, e.g. from a later page
+			ID: "2", Title: "Who wins? (copy)", Active: true, NegRisk: true, NegRiskMarketID: "0xaa",
+			Markets: []gamma.Market{binary("21", "A again?", "0.10", "0.90", 0)},
+		},
+	}
+
+	r := Scan(events, ScanOptions{NegRiskThreshold: 0.005})
+	for _, f := range r.Findings {
+		if f.Kind == NegRiskUnder || f.Kind == NegRiskOver {
+			t.Errorf("Expected group 0xaa to sum to 1 and be checked once, got %+v", f)
+		}
+	}
+}
+
+func TestScanOptions(t *testing.T) {
+	r := Scan(scanEvents, ScanOptions{NegRiskThreshold: 0.005, BinarySlack: 0.1, IncludeInactive: true})
+
+	counts := map[FindingKind]int{}
+	for _, f := range r.Findings {
+		counts[f.Kind]++
+	}
+	// events 1 and 5 under, event 2 over; slack hides every binary market
+	// except the inactive one priced at 0.1/0.1
+	if counts[NegRiskUnder] != 2 || counts[NegRiskOver] != 1 || counts[BinaryMispriced] != 1 {
+		t.Errorf("Unexpected findings %v", counts)
+	}
+}
+
+func TestScanPages(t *testing.T) {
+	var offsets []int
+	pager := func(limit, offset int) ([]gamma.Event, error) {
+		offsets = append(offsets, offset)
+		end := min(offset+limit, len(scanEvents))
+		return scanEvents[offset:end], nil
+	}
+
+	r, err := ScanPages(t.Context(), pager, 2, 0, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanPages failed: %v", err)
+	}
+	if len(offsets) != 3 || r.Events != 4 {
+		t.Errorf("Expected 3 pages and 4 events, got %v and %d", offsets, r.Events)
+	}
+	// events 3 and 4 are on different pages
+	if r.Findings[0].Kind != DuplicateQuestion {
+		t.Errorf("Expected duplicate across pages, got %v", r.Findings[0].Kind)
+	}
+
+	boom := errors.New("boom")
+	_, err = ScanPages(t.Context(), func(int, int) ([]gamma.Event, error) { return nil, boom }, 2, 0, ScanOptions{})
+	if !errors.Is(err, boom) {
+		t.Errorf("Expected pager error, got %v", err)
+	}
+}
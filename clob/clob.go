@@ -0,0 +1,187 @@
+// gammago/clob/clob.go
+
+// Package clob reads order books and prices from Polymarket's CLOB
+//
+// Requests go through gammago.GetRawFrom, so the HTTP client, retries,
+// middleware, hooks, logging, metrics and tracing configured on the main
+// package apply here too. Only the public read-only endpoints are covered
+package clob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+const BaseURL = "https://clob.polymarket.com"
+
+var (
+	configMu sync.RWMutex
+	baseURL  = BaseURL
+)
+
+// SetBaseURL points requests at another CLOB-compatible host, such as a
+// test server. Pass "" to go back to BaseURL
+func SetBaseURL(u string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if u == "" {
+		u = BaseURL
+	}
+	baseURL = u
+}
+
+func getBaseURL() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return baseURL
+}
+
+// Level is one price level of a book
+type Level struct {
+	Price float64 `json:"price,string"`
+	Size  float64 `json:"size,string"`
+}
+
+// OrderBook is the book for one outcome token
+// Bids and Asks are in the order the CLOB sends them; use BestBid and
+// BestAsk rather than indexing
+type OrderBook struct {
+	Market       string  `json:"market"`
+	AssetID      string  `json:"asset_id"`
+	Hash         string  `json:"hash"`
+	Timestamp    string  `json:"timestamp"`
+	Bids         []Level `json:"bids"`
+	Asks         []Level `json:"asks"`
+	MinOrderSize string  `json:"min_order_size"`
+	TickSize     string  `json:"tick_size"`
+	NegRisk      bool    `json:"neg_risk"`
+}
+
+// BestBid is the highest bid, false if there are no bids
+func (b OrderBook) BestBid() (Level, bool) {
+	return best(b.Bids, func(p, q float64) bool { return p > q })
+}
+
+// BestAsk is the lowest ask, false if there are no asks
+func (b OrderBook) BestAsk() (Level, bool) {
+	return best(b.Asks, func(p, q float64) bool { return p < q })
+}
+
+// Midpoint is halfway between the best bid and ask, false if either side
+// is empty
+func (b OrderBook) Midpoint() (float64, bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return (bid.Price + ask.Price) / 2, true
+}
+
+func best(levels []Level, better func(p, q float64) bool) (Level, bool) {
+	if len(levels) == 0 {
+		return Level{}, false
+	}
+	b := levels[0]
+	for _, l := range levels[1:] {
+		if better(l.Price, b.Price) {
+			b = l
+		}
+	}
+	return b, true
+}
+
+// LastTrade is the most recent trade of a token
+type LastTrade struct {
+	Price float64 `json:"price,string"`
+	Side  string  `json:"side"`
+}
+
+// PricePoint is one point of a token's price history
+type PricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// PriceHistoryParams selects the range of GetPriceHistory
+// Set either Interval (e.g. "1h", "1d", "1w", "max") or Start and End
+// Fidelity is the resolution in minutes; 0 leaves it to the CLOB
+type PriceHistoryParams struct {
+	Interval string
+	Start    time.Time
+	End      time.Time
+	Fidelity int
+}
+
+// GetOrderBook gets the book for tokenID
+func GetOrderBook(ctx context.Context, tokenID string) (OrderBook, error) {
+	var book OrderBook
+	err := get(ctx, "/book", url.Values{"token_id": {tokenID}}, &book)
+	return book, err
+}
+
+// GetMidpoint gets the CLOB's midpoint price for tokenID
+func GetMidpoint(ctx context.Context, tokenID string) (float64, error) {
+	var resp struct {
+		Mid float64 `json:"mid,string"`
+	}
+	err := get(ctx, "/midpoint", url.Values{"token_id": {tokenID}}, &resp)
+	return resp.Mid, err
+}
+
+// GetLastTradePrice gets the price and side of the last trade of tokenID
+func GetLastTradePrice(ctx context.Context, tokenID string) (LastTrade, error) {
+	var trade LastTrade
+	err := get(ctx, "/last-trade-price", url.Values{"token_id": {tokenID}}, &trade)
+	return trade, err
+}
+
+// GetPriceHistory gets the price history of tokenID, oldest first
+func GetPriceHistory(ctx context.Context, tokenID string, p PriceHistoryParams) ([]PricePoint, error) {
+	params := url.Values{"market": {tokenID}}
+	if p.Interval != "" {
+		params.Set("interval", p.Interval)
+	}
+	if !p.Start.IsZero() {
+		params.Set("startTs", strconv.FormatInt(p.Start.Unix(), 10))
+	}
+	if !p.End.IsZero() {
+		params.Set("endTs", strconv.FormatInt(p.End.Unix(), 10))
+	}
+	if p.Fidelity > 0 {
+		params.Set("fidelity", strconv.Itoa(p.Fidelity))
+	}
+
+	var resp struct {
+		History []struct {
+			T int64   `json:"t"`
+			P float64 `json:"p"`
+		} `json:"history"`
+	}
+	if err := get(ctx, "/prices-history", params, &resp); err != nil {
+		return nil, err
+	}
+	points := make([]PricePoint, len(resp.History))
+	for i, h := range resp.History {
+		points[i] = PricePoint{Time: time.Unix(h.T, 0).UTC(), Price: h.P}
+	}
+	return points, nil
+}
+
+func get(ctx context.Context, path string, params url.Values, v any) error {
+	body, err := gamma.GetRawFrom(ctx, getBaseURL(), path, params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("clob: decoding %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// gammago/clob/clob_test.go
+
+package clob
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func fakeCLOB(t *testing.T) *[]string {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		mu.Unlock()
+		token := r.URL.Query().Get("token_id")
+		switch {
+		case r.URL.Path == "/book" && token == "yes":
+			w.Write([]byte(`{"market":"0xc1","asset_id":"yes","hash":"h1","timestamp":"1760000000000",
+				"bids":[{"price":"0.40","size":"100"},{"price":"0.44","size":"25"}],
+				"asks":[{"price":"0.50","size":"10"},{"price":"0.47","size":"30"}],
+				"min_order_size":"5","tick_size":"0.01","neg_risk":false}`))
+		case r.URL.Path == "/book" && token == "no":
+			w.Write([]byte(`{"market":"0xc1","asset_id":"no","bids":[{"price":"0.53","size":"20"}],"asks":[]}`))
+		case r.URL.Path == "/midpoint":
+			w.Write([]byte(`{"mid":"0.455"}`))
+		case r.URL.Path == "/last-trade-price":
+			w.Write([]byte(`{"price":"0.45","side":"BUY"}`))
+		case r.URL.Path == "/prices-history":
+			w.Write([]byte(`{"history":[{"t":1760000000,"p":0.41},{"t":1760003600,"p":0.45}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"No orderbook exists for the requested token id"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	SetBaseURL(srv.URL)
+	t.Cleanup(func() { SetBaseURL("") })
+	return &queries
+}
+
+func TestGetOrderBook(t *testing.T) {
+	fakeCLOB(t)
+
+	book, err := GetOrderBook(t.Context(), "yes")
+	if err != nil {
+		t.Fatalf("GetOrderBook failed: %v", err)
+	}
+	if book.AssetID != "yes" || len(book.Bids) != 2 || book.Bids[0] != (Level{Price: 0.40, Size: 100}) || book.TickSize != "0.01" {
+		t.Errorf("Unexpected book %+v", book)
+	}
+
+	bid, _ := book.BestBid()
+	ask, _ := book.BestAsk()
+	mid, ok := book.Midpoint()
+	if bid.Price != 0.44 || ask.Price != 0.47 || !ok || mid < 0.4549 || mid > 0.4551 {
+		t.Errorf("Expected bid 0.44 ask 0.47 mid 0.455, got %v %v %v", bid.Price, ask.Price, mid)
+	}
+
+	if _, ok := (OrderBook{}).Midpoint(); ok {
+		t.Error("Expected no midpoint for an empty book")
+	}
+
+	_, err = GetOrderBook(t.Context(), "missing")
+	var se *gamma.StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a 404 StatusError, got %v", err)
+	}
+}
+
+func TestPrices(t *testing.T) {
+	queries := fakeCLOB(t)
+
+	mid, err := GetMidpoint(t.Context(), "yes")
+	if err != nil || mid != 0.455 {
+		t.Errorf("Expected midpoint 0.455, got %v %v", mid, err)
+	}
+
+	trade, err := GetLastTradePrice(t.Context(), "yes")
+	if err != nil || trade != (LastTrade{Price: 0.45, Side: "BUY"}) {
+		t.Errorf("Unexpected last trade %+v %v", trade, err)
+	}
+
+	start := time.Unix(1760000000, 0)
+	points, err := GetPriceHistory(t.Context(), "yes", PriceHistoryParams{Start: start, End: start.Add(time.Hour), Fidelity: 60})
+	if err != nil {
+		t.Fatalf("GetPriceHistory failed: %v", err)
+	}
+	if len(points) != 2 || !points[1].Time.Equal(start.Add(time.Hour)) || points[1].Price != 0.45 {
+		t.Errorf("Unexpected history %+v", points)
+	}
+	want := "/prices-history?endTs=1760003600&fidelity=60&market=yes&startTs=1760000000"
+	if got := (*queries)[len(*queries)-1]; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	fakeCLOB(t)
+
+	m := gamma.Market{ID: "501", Outcomes: `["Yes", "No"]`, CLOBTokenIDs: `["yes", "no"]`}
+	mb, err := Enrich(t.Context(), m)
+	if err != nil {
+		t.Fatalf("Enrich failed: %v", err)
+	}
+	if mb.Market.ID != "501" || len(mb.Outcomes) != 2 {
+		t.Fatalf("Unexpected market book %+v", mb)
+	}
+	yes, no := mb.Outcomes[0], mb.Outcomes[1]
+	if yes.Outcome != "Yes" || yes.TokenID != "yes" || yes.BestBid != 0.44 || yes.BestAsk != 0.47 || yes.Midpoint == 0 {
+		t.Errorf("Unexpected Yes book %+v", yes)
+	}
+	if no.Outcome != "No" || no.BestBid != 0.53 || no.BestAsk != 0 || no.Midpoint != 0 {
+		t.Errorf("Unexpected No book %+v", no)
+	}
+
+	if _, err := Enrich(t.Context(), gamma.Market{ID: "502"}); !errors.Is(err, ErrNoTokens) {
+		t.Errorf("Expected ErrNoTokens, got %v", err)
+	}
+
+	m.CLOBTokenIDs = `["yes", "missing"]`
+	var se *gamma.StatusError
+	if _, err := Enrich(t.Context(), m); !errors.As(err, &se) {
+		t.Errorf("Expected the failing token's StatusError, got %v", err)
+	}
+}
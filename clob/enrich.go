@@ -0,0 +1,80 @@
+// gammago/clob/enrich.go
+
+package clob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// ErrNoTokens is returned by Enrich for markets without CLOB token IDs
+var ErrNoTokens = errors.New("clob: market has no CLOB token IDs")
+
+// OutcomeBook is the live book of one outcome of a market
+type OutcomeBook struct {
+	Outcome  string
+	TokenID  string
+	Book     OrderBook
+	BestBid  float64 // 0 when there are no bids
+	BestAsk  float64 // 0 when there are no asks
+	Midpoint float64 // 0 when either side is empty
+}
+
+// MarketBook is a Gamma market joined with the CLOB books of its outcomes
+// Outcomes are in the order of Market.CLOBTokenIDs
+type MarketBook struct {
+	Market   gamma.Market
+	Outcomes []OutcomeBook
+}
+
+// Enrich fetches the book of every outcome token of m concurrently
+// The first error cancels the rest and is returned
+func Enrich(ctx context.Context, m gamma.Market) (MarketBook, error) {
+	var tokens []string
+	if err := json.Unmarshal([]byte(m.CLOBTokenIDs), &tokens); err != nil || len(tokens) == 0 {
+		return MarketBook{}, fmt.Errorf("market %s: %w", m.ID, ErrNoTokens)
+	}
+	var outcomes []string
+	json.Unmarshal([]byte(m.Outcomes), &outcomes)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	mb := MarketBook{Market: m, Outcomes: make([]OutcomeBook, len(tokens))}
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		ob := &mb.Outcomes[i]
+		ob.TokenID = token
+		if i < len(outcomes) {
+			ob.Outcome = outcomes[i]
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			book, err := GetOrderBook(ctx, token)
+			if err != nil {
+				cancel(fmt.Errorf("market %s token %s: %w", m.ID, token, err))
+				return
+			}
+			ob.Book = book
+			if bid, ok := book.BestBid(); ok {
+				ob.BestBid = bid.Price
+			}
+			if ask, ok := book.BestAsk(); ok {
+				ob.BestAsk = ask.Price
+			}
+			ob.Midpoint, _ = book.Midpoint()
+		}()
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return MarketBook{}, err
+	}
+	return mb, nil
+}
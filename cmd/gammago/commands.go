@@ -0,0 +1,240 @@
+// gammago/cmd/gammago/commands.go
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// stringList is a repeatable flag that also splits on commas
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// dateFlag accepts 2006-01-02 or RFC 3339
+type dateFlag struct {
+	time.Time
+}
+
+func (d *dateFlag) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(time.RFC3339)
+}
+
+func (d *dateFlag) Set(v string) error {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			d.Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q (want YYYY-MM-DD or RFC 3339)", v)
+}
+
+func parseStatus(s string) (gamma.Status, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return "", nil
+	case "active":
+		return gamma.ACTIVE, nil
+	case "closed":
+		return gamma.CLOSED, nil
+	}
+	return "", fmt.Errorf("invalid status %q (want active or closed)", s)
+}
+
+// newFlagSet returns a flag set with the shared -o flag
+func newFlagSet(name, argsUsage string, out io.Writer) (*flag.FlagSet, *string) {
+	fs := newBaseFlagSet(name, argsUsage, out)
+	format := fs.String("o", formatTable, "output format: table, json, ndjson or pretty")
+	return fs, format
+}
+
+// newBaseFlagSet returns a flag set without -o, for commands with their
+// own output
+func newBaseFlagSet(name, argsUsage string, out io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.Usage = func() {
+		fmt.Fprintln(out, strings.TrimSpace("usage: gammago "+name+" [flags] "+argsUsage))
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func eventsList(args []string, out io.Writer) error {
+	fs, format := newFlagSet("events list", "", out)
+	limit := fs.Int("limit", 50, "page size")
+	offset := fs.Int("offset", 0, "page offset")
+	tag := fs.Int("tag", 0, "tag ID")
+	related := fs.Bool("related", false, "include events from related tags (tag only)")
+	volumeMin := fs.Int("volume-min", 0, "minimum volume (date range only)")
+	status := fs.String("status", "", "active or closed (date range only)")
+	var start, end dateFlag
+	fs.Var(&start, "start", "earliest end date")
+	fs.Var(&end, "end", "latest end date")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	st, err := parseStatus(*status)
+	if err != nil {
+		return err
+	}
+
+	var events []gamma.Event
+	switch {
+	case !end.IsZero() && !start.IsZero():
+		events, err = gamma.GetEventsBetweenDates(*limit, *offset, *volumeMin, *tag, end.Time, start.Time, st)
+	case !end.IsZero():
+		events, err = gamma.GetEventsBeforeDate(*limit, *offset, *volumeMin, *tag, end.Time, st)
+	case *tag != 0:
+		events, err = gamma.GetEventsByTag(*tag, *related)
+	default:
+		return errors.New("events list needs -tag or -end")
+	}
+	if err != nil {
+		return err
+	}
+
+	return render(out, *format, events, eventColumns)
+}
+
+func eventsGet(args []string, out io.Writer) error {
+	fs, format := newFlagSet("events get", "<id>...", out)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("events get needs at least one event ID")
+	}
+
+	events := make([]gamma.Event, 0, fs.NArg())
+	for _, id := range fs.Args() {
+		e, err := gamma.GetEventByID(id)
+		if err != nil {
+			return fmt.Errorf("event %s: %w", id, err)
+		}
+		events = append(events, e)
+	}
+
+	return render(out, *format, events, eventColumns)
+}
+
+func marketsGet(args []string, out io.Writer) error {
+	fs, format := newFlagSet("markets get", "<id>...", out)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("markets get needs at least one market ID")
+	}
+
+	var markets []gamma.Market
+	for _, arg := range fs.Args() {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid market ID %q", arg)
+		}
+		m, err := gamma.GetMarketByID(id)
+		if err != nil {
+			return fmt.Errorf("market %d: %w", id, err)
+		}
+		markets = append(markets, m...)
+	}
+
+	return render(out, *format, markets, marketColumns)
+}
+
+func tagsRelated(args []string, out io.Writer) error {
+	fs, format := newFlagSet("tags related", "<tag-id>", out)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("tags related needs exactly one tag ID")
+	}
+
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid tag ID %q", fs.Arg(0))
+	}
+
+	tags, err := gamma.GetRelatedTagsByTagId(id)
+	if err != nil {
+		return err
+	}
+
+	return render(out, *format, tags, tagColumns)
+}
+
+func teamsList(args []string, out io.Writer) error {
+	fs, format := newFlagSet("teams list", "", out)
+	limit := fs.Int("limit", 50, "page size")
+	offset := fs.Int("offset", 0, "page offset")
+	var league, name, abbreviation stringList
+	fs.Var(&league, "league", "league filter (repeatable or comma separated)")
+	fs.Var(&name, "name", "team name filter (repeatable or comma separated)")
+	fs.Var(&abbreviation, "abbr", "abbreviation filter (repeatable or comma separated)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	teams, err := gamma.GetTeams(*limit, *offset, league, name, abbreviation)
+	if err != nil {
+		return err
+	}
+
+	return render(out, *format, teams, teamColumns)
+}
+
+func sports(args []string, out io.Writer) error {
+	fs, format := newFlagSet("sports", "", out)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := gamma.GetSports()
+	if err != nil {
+		return err
+	}
+
+	return render(out, *format, s, sportColumns)
+}
+
+func series(args []string, out io.Writer) error {
+	fs, format := newFlagSet("series", "", out)
+	limit := fs.Int("limit", 50, "page size")
+	offset := fs.Int("offset", 0, "page offset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := gamma.GetSeries(*limit, *offset)
+	if err != nil {
+		return err
+	}
+
+	return render(out, *format, s, seriesColumns)
+}
@@ -0,0 +1,91 @@
+// gammago/cmd/gammago/drift.go
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+var errDrift = errors.New("schema drift found")
+
+// driftCmd compares recorded or live responses against the Go types
+// It fails when anything other than missing fields is found, so it can
+// run as a nightly check
+func driftCmd(args []string, out io.Writer) error {
+	fs := newBaseFlagSet("drift", "", out)
+	var events, markets stringList
+	fs.Var(&events, "events", "recorded /events response `file` (repeatable)")
+	fs.Var(&markets, "markets", "recorded /markets response `file` (repeatable)")
+	live := fs.Bool("live", false, "also check the most recently updated events and markets")
+	limit := fs.Int("limit", 100, "events and markets fetched with -live")
+	missing := fs.Bool("missing", false, "report declared fields absent from responses")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(events) == 0 && len(markets) == 0 && !*live {
+		return errors.New("nothing to check: pass -events, -markets or -live")
+	}
+
+	gamma.ResetDrift()
+	for _, f := range events {
+		if err := checkFile[gamma.Event]("/events", f); err != nil {
+			return err
+		}
+	}
+	for _, f := range markets {
+		if err := checkFile[gamma.Market]("/markets", f); err != nil {
+			return err
+		}
+	}
+	if *live {
+		gamma.SetStrictMode(true)
+		defer gamma.SetStrictMode(false)
+		if _, err := gamma.GetEventsByUpdatedAt(*limit, 0); err != nil {
+			return err
+		}
+		if _, err := gamma.GetMarketsByUpdatedAt(*limit, 0); err != nil {
+			return err
+		}
+	}
+
+	report := gamma.GetDriftReport()
+	if !*missing {
+		kept := report.Drifts[:0]
+		for _, d := range report.Drifts {
+			if d.Kind != gamma.MissingField {
+				kept = append(kept, d)
+			}
+		}
+		report.Drifts = kept
+	}
+	fmt.Fprint(out, report)
+	for _, d := range report.Drifts {
+		if d.Kind != gamma.MissingField {
+			return errDrift
+		}
+	}
+	return nil
+}
+
+// checkFile checks a recorded body holding one T or an array of them
+func checkFile[T any](endpoint, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = gamma.CheckDrift[[]T](endpoint, b)
+	} else {
+		err = gamma.CheckDrift[T](endpoint, b)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
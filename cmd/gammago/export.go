@@ -0,0 +1,143 @@
+// gammago/cmd/gammago/export.go
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	gamma "github.com/Bazcampbell/gammago"
+	"github.com/Bazcampbell/gammago/export"
+	"github.com/Bazcampbell/gammago/export/parquet"
+)
+
+func exportCmd(args []string, out io.Writer) (err error) {
+	fs := newBaseFlagSet("export", "", out)
+	kind := fs.String("kind", "markets", "what to export: markets or events")
+	format := fs.String("format", "csv", "file format: csv, ndjson or parquet")
+	var columns stringList
+	fs.Var(&columns, "columns", "CSV columns (repeatable or comma separated, default set if empty)")
+	outPath := fs.String("out", "", "output file (default stdout)")
+	rowGroupSize := fs.Int("row-group-size", parquet.DefaultRowGroupSize, "rows per row group (parquet only)")
+	codec := fs.String("codec", "none", "page compression: none or gzip (parquet only)")
+	limit := fs.Int("limit", 500, "page size")
+	maxPages := fs.Int("max-pages", 0, "stop after this many pages (0 = all)")
+	tag := fs.Int("tag", 0, "tag ID (events only)")
+	volumeMin := fs.Int("volume-min", 0, "minimum volume (events only)")
+	status := fs.String("status", "", "active or closed (events only)")
+	var start, end dateFlag
+	fs.Var(&start, "start", "earliest date")
+	fs.Var(&end, "end", "latest end date")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if start.IsZero() || end.IsZero() {
+		return errors.New("export needs -start and -end")
+	}
+	if *limit <= 0 {
+		return errors.New("-limit must be positive")
+	}
+	st, err := parseStatus(*status)
+	if err != nil {
+		return err
+	}
+	opts := parquet.Options{RowGroupSize: *rowGroupSize}
+	switch *codec {
+	case "none":
+		opts.Codec = parquet.Uncompressed
+	case "gzip":
+		opts.Codec = parquet.Gzip
+	default:
+		return fmt.Errorf("unknown codec %q (want none or gzip)", *codec)
+	}
+
+	w := out
+	if *outPath != "" {
+		f, createErr := os.Create(*outPath)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+
+	switch *kind {
+	case "markets":
+		mw, err := newExportWriter(*format, columns, opts, w,
+			export.NewMarketCSV, export.NewNDJSON[gamma.Market], parquet.NewMarketWriter)
+		if err != nil {
+			return err
+		}
+		return exportPages(mw, *limit, *maxPages, stderr, func(offset int, fn func(gamma.Market) error) error {
+			return gamma.StreamMarketsBetweenDates(*limit, offset, start.Time, end.Time, fn)
+		})
+
+	case "events":
+		ew, err := newExportWriter(*format, columns, opts, w,
+			export.NewEventCSV, export.NewNDJSON[gamma.Event], parquet.NewEventWriter)
+		if err != nil {
+			return err
+		}
+		return exportPages(ew, *limit, *maxPages, stderr, func(offset int, fn func(gamma.Event) error) error {
+			return gamma.StreamEventsBetweenDates(*limit, offset, *volumeMin, *tag, end.Time, start.Time, st, fn)
+		})
+	}
+
+	return fmt.Errorf("unknown kind %q (want markets or events)", *kind)
+}
+
+func newExportWriter[T any, C export.Writer[T]](
+	format string,
+	columns []string,
+	opts parquet.Options,
+	w io.Writer,
+	newCSV func(io.Writer, ...string) (C, error),
+	newNDJSON func(io.Writer) *export.NDJSONWriter[T],
+	newParquet func(io.Writer, parquet.Options) *parquet.Writer[T],
+) (export.Writer[T], error) {
+	switch format {
+	case "csv":
+		return newCSV(w, columns...)
+	case "ndjson":
+		return newNDJSON(w), nil
+	case "parquet":
+		return newParquet(w, opts), nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want csv, ndjson or parquet)", format)
+}
+
+// exportPages streams page after page into w until a short page comes back
+// Records w rejects are skipped and reported to errOut. w is flushed even
+// when a page fails, so the rows written so far make a complete file
+func exportPages[T any](w export.Writer[T], limit, maxPages int, errOut io.Writer, stream func(offset int, fn func(T) error) error) error {
+	var err error
+	skipped := 0
+	for page := 0; maxPages == 0 || page < maxPages; page++ {
+		n := 0
+		err = stream(page*limit, func(v T) error {
+			n++
+			err := w.Write(v)
+			var rowErr *export.RowError
+			if errors.As(err, &rowErr) {
+				skipped++
+				fmt.Fprintln(errOut, "gammago: skipped:", err)
+				return nil
+			}
+			return err
+		})
+		if err != nil || n < limit {
+			break
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(errOut, "gammago: skipped %d records\n", skipped)
+	}
+	return errors.Join(err, w.Flush())
+}
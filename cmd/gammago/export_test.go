@@ -0,0 +1,104 @@
+// gammago/cmd/gammago/export_test.go
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	gamma "github.com/Bazcampbell/gammago"
+	"github.com/Bazcampbell/gammago/export"
+	"github.com/Bazcampbell/gammago/export/parquet"
+)
+
+func TestExportPages(t *testing.T) {
+	// 5 records served in pages of 2
+	all := []gamma.Tag{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}
+	stream := func(offset int, fn func(gamma.Tag) error) error {
+		for i := offset; i < min(offset+2, len(all)); i++ {
+			if err := fn(all[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		maxPages int
+		want     string
+	}{
+		{"all pages", 0, "1,2,3,4,5"},
+		{"page cap", 2, "1,2,3,4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := export.NewCSV(&buf, []export.Column[gamma.Tag]{{Name: "id", Value: func(t gamma.Tag) string { return t.ID }}})
+			if err := exportPages(w, 2, tt.maxPages, io.Discard, stream); err != nil {
+				t.Fatalf("exportPages failed: %v", err)
+			}
+			got := strings.Join(strings.Split(strings.TrimSpace(buf.String()), "\n")[1:], ",")
+			if got != tt.want {
+				t.Errorf("exported ids = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportPagesBadRecords(t *testing.T) {
+	good := gamma.Market{ID: "1", OutcomePrices: `["0.4", "0.6"]`}
+	bad := gamma.Market{ID: "2", OutcomePrices: `["0.4", "n/a"]`}
+	fail := errors.New("page 2 failed")
+	stream := func(offset int, fn func(gamma.Market) error) error {
+		if offset > 0 {
+			return fail
+		}
+		for _, m := range []gamma.Market{good, bad} {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf, errOut bytes.Buffer
+	err := exportPages(parquet.NewMarketWriter(&buf, parquet.Options{}), 2, 0, &errOut, stream)
+	if !errors.Is(err, fail) {
+		t.Errorf("Expected the page error, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "market 2") || !strings.Contains(errOut.String(), "skipped 1 records") {
+		t.Errorf("Expected the bad record reported, got %q", errOut.String())
+	}
+	// the footer was still written
+	if b := buf.Bytes(); len(b) < 8 || string(b[:4]) != "PAR1" || string(b[len(b)-4:]) != "PAR1" {
+		t.Errorf("Expected a complete parquet file, got %d bytes", len(b))
+	}
+}
+
+func TestExportFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"missing dates", nil, "needs -start and -end"},
+		{"bad kind", []string{"-start", "2026-01-01", "-end", "2026-02-01", "-kind", "teams"}, "unknown kind"},
+		{"bad format", []string{"-start", "2026-01-01", "-end", "2026-02-01", "-format", "xml"}, "unknown format"},
+		{"bad codec", []string{"-start", "2026-01-01", "-end", "2026-02-01", "-format", "parquet", "-codec", "snappy"}, "unknown codec"},
+		{"bad column", []string{"-start", "2026-01-01", "-end", "2026-02-01", "-columns", "nope"}, "unknown column"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := exportCmd(tt.args, &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
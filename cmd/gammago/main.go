@@ -0,0 +1,77 @@
+// gammago/cmd/gammago/main.go
+
+// Command gammago queries the Polymarket Gamma API from the shell
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const usage = `usage: gammago <command> [flags]
+
+commands:
+  events list    list events by tag or date range
+  events get     get events by ID
+  markets get    get markets by ID
+  tags related   get tags related to a tag ID
+  teams list     list teams
+  sports         list sports
+  series         list series
+  watch          poll markets and show price changes
+  export         export markets or events to CSV or NDJSON
+  drift          check responses against the Go types
+
+Run "gammago <command> -h" for command flags.
+`
+
+// stderr gets messages that mustn't mix with a command's output
+var stderr io.Writer = os.Stderr
+
+// command runs one subcommand with the arguments after its name
+type command func(args []string, out io.Writer) error
+
+var commands = map[string]command{
+	"events list":  eventsList,
+	"events get":   eventsGet,
+	"markets get":  marketsGet,
+	"tags related": tagsRelated,
+	"teams list":   teamsList,
+	"sports":       sports,
+	"series":       series,
+	"watch":        watch,
+	"export":       exportCmd,
+	"drift":        driftCmd,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, "gammago:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run resolves one- and two-word command names and dispatches
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		fmt.Fprint(out, usage)
+		return nil
+	}
+
+	if len(args) > 1 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return cmd(args[2:], out)
+		}
+	}
+	if cmd, ok := commands[args[0]]; ok {
+		return cmd(args[1:], out)
+	}
+
+	return fmt.Errorf("unknown command %q\n\n%s", strings.Join(args[:min(2, len(args))], " "), usage)
+}
@@ -0,0 +1,171 @@
+// gammago/cmd/gammago/main_test.go
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func TestRender(t *testing.T) {
+	tags := []gamma.Tag{
+		{ID: "1", Label: "Sports", Slug: "sports"},
+		{ID: "2", Label: "Politics", Slug: "politics"},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "table",
+			format: formatTable,
+			want:   "ID  LABEL     SLUG\n1   Sports    sports\n2   Politics  politics\n",
+		},
+		{
+			name:   "ndjson",
+			format: formatNDJSON,
+			want:   "{\"id\":\"1\",\"label\":\"Sports\",\"slug\":\"sports\"}\n{\"id\":\"2\",\"label\":\"Politics\",\"slug\":\"politics\"}\n",
+		},
+		{
+			name:   "pretty uses String()",
+			format: formatPretty,
+			want:   "Tag{ID: 1, Label: Sports, Slug: sports}\nTag{ID: 2, Label: Politics, Slug: politics}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := render(&buf, tt.format, tags, tagColumns); err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("render output mismatch\nwant: %q\ngot:  %q", tt.want, buf.String())
+			}
+		})
+	}
+
+	t.Run("json is an indented array", func(t *testing.T) {
+		var buf bytes.Buffer
+		render(&buf, formatJSON, tags, tagColumns)
+		if !strings.HasPrefix(buf.String(), "[\n  {") {
+			t.Errorf("unexpected json output: %q", buf.String())
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := render(&bytes.Buffer{}, "xml", tags, tagColumns); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+
+	t.Run("long cells are truncated", func(t *testing.T) {
+		got := truncate(strings.Repeat("a", 100))
+		if len(got) != maxCellWidth || !strings.HasSuffix(got, "...") {
+			t.Errorf("truncate = %q", got)
+		}
+	})
+}
+
+func TestFlags(t *testing.T) {
+	t.Run("stringList splits and repeats", func(t *testing.T) {
+		var l stringList
+		l.Set("nfl, nba")
+		l.Set("mlb")
+		if l.String() != "nfl,nba,mlb" {
+			t.Errorf("stringList = %q", l.String())
+		}
+	})
+
+	t.Run("dateFlag", func(t *testing.T) {
+		var d dateFlag
+		if err := d.Set("2026-01-15"); err != nil || !d.Equal(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("date = %v, err = %v", d.Time, err)
+		}
+		if err := d.Set("2026-01-15T19:30:00Z"); err != nil || d.Hour() != 19 {
+			t.Errorf("date = %v, err = %v", d.Time, err)
+		}
+		if err := d.Set("15/01/2026"); err == nil {
+			t.Error("expected error for bad date")
+		}
+	})
+
+	t.Run("parseStatus", func(t *testing.T) {
+		if s, _ := parseStatus("Active"); s != gamma.ACTIVE {
+			t.Errorf("status = %q", s)
+		}
+		if _, err := parseStatus("open"); err == nil {
+			t.Error("expected error for unknown status")
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no args prints usage", nil, ""},
+		{"unknown command", []string{"widgets"}, "unknown command"},
+		{"events list without filters", []string{"events", "list"}, "needs -tag or -end"},
+		{"events get without ids", []string{"events", "get"}, "at least one event ID"},
+		{"markets get with bad id", []string{"markets", "get", "abc"}, "invalid market ID"},
+		{"bad status flag", []string{"events", "list", "-status", "open", "-tag", "1"}, "invalid status"},
+		{"watch has no -o", []string{"watch", "-o", "json", "-markets", "1"}, "flag provided but not defined: -o"},
+		{"export has no -o", []string{"export", "-o", "json"}, "flag provided but not defined: -o"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := run(tt.args, &buf)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDrift(t *testing.T) {
+	dir := t.TempDir()
+	clean := dir + "/clean.json"
+	drifted := dir + "/drifted.json"
+	os.WriteFile(clean, []byte(`[{"id":"1","title":"E","active":true}]`), 0o644)
+	os.WriteFile(drifted, []byte(`{"id":"1","title":"E","volume":"12","brandNew":1}`), 0o644)
+
+	var buf bytes.Buffer
+	if err := run([]string{"drift", "-events", clean}, &buf); err != nil {
+		t.Fatalf("clean sample failed: %v\n%s", err, buf.String())
+	}
+	if buf.String() != "no drift\n" {
+		t.Errorf("missing fields should be hidden by default, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	err := run([]string{"drift", "-events", clean, "-events", drifted}, &buf)
+	if !errors.Is(err, errDrift) {
+		t.Errorf("expected errDrift, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "brandNew") || !strings.Contains(buf.String(), "got string, want number") {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+
+	if err := run([]string{"drift"}, &buf); err == nil {
+		t.Error("expected an error with nothing to check")
+	}
+}
@@ -0,0 +1,145 @@
+// gammago/cmd/gammago/output.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// Output formats accepted by -o
+const (
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatPretty = "pretty"
+)
+
+const maxCellWidth = 60
+
+// column is one table column
+type column[T any] struct {
+	header string
+	value  func(T) string
+}
+
+// render writes items in the requested format
+// pretty uses the String() formatters from types_string.go
+func render[T fmt.Stringer](out io.Writer, format string, items []T, cols []column[T]) error {
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		headers := make([]string, len(cols))
+		for i, c := range cols {
+			headers[i] = c.header
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+		for _, item := range items {
+			cells := make([]string, len(cols))
+			for i, c := range cols {
+				cells[i] = truncate(c.value(item))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+
+	case formatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+
+	case formatNDJSON:
+		enc := json.NewEncoder(out)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case formatPretty:
+		for _, item := range items {
+			if _, err := fmt.Fprintln(out, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format %q (want table, json, ndjson or pretty)", format)
+}
+
+func truncate(s string) string {
+	s = strings.ReplaceAll(s, "\t", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	if r := []rune(s); len(r) > maxCellWidth {
+		return string(r[:maxCellWidth-3]) + "..."
+	}
+	return s
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func formatMoney(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+var eventColumns = []column[gamma.Event]{
+	{"ID", func(e gamma.Event) string { return e.ID }},
+	{"TITLE", func(e gamma.Event) string { return e.Title }},
+	{"ACTIVE", func(e gamma.Event) string { return strconv.FormatBool(e.Active) }},
+	{"END", func(e gamma.Event) string { return formatDate(e.EndDate) }},
+	{"VOLUME", func(e gamma.Event) string { return formatMoney(e.Volume) }},
+	{"MARKETS", func(e gamma.Event) string { return strconv.Itoa(len(e.Markets)) }},
+}
+
+var marketColumns = []column[gamma.Market]{
+	{"ID", func(m gamma.Market) string { return m.ID }},
+	{"QUESTION", func(m gamma.Market) string { return m.Question }},
+	{"ACTIVE", func(m gamma.Market) string { return strconv.FormatBool(m.Active) }},
+	{"END", func(m gamma.Market) string { return formatDate(m.EndDate) }},
+	{"PRICES", func(m gamma.Market) string { return m.OutcomePrices }},
+	{"VOLUME", func(m gamma.Market) string { return m.Volume }},
+	{"LIQUIDITY", func(m gamma.Market) string { return m.Liquidity }},
+}
+
+var tagColumns = []column[gamma.Tag]{
+	{"ID", func(t gamma.Tag) string { return t.ID }},
+	{"LABEL", func(t gamma.Tag) string { return t.Label }},
+	{"SLUG", func(t gamma.Tag) string { return t.Slug }},
+}
+
+var teamColumns = []column[gamma.Team]{
+	{"ID", func(t gamma.Team) string { return strconv.Itoa(t.ID) }},
+	{"NAME", func(t gamma.Team) string { return t.Name }},
+	{"LEAGUE", func(t gamma.Team) string { return t.League }},
+	{"ABBR", func(t gamma.Team) string { return t.Abbreviation }},
+}
+
+var sportColumns = []column[gamma.Sport]{
+	{"SPORT", func(s gamma.Sport) string { return s.Sport }},
+	{"SERIES", func(s gamma.Sport) string { return s.Series }},
+	{"TAGS", func(s gamma.Sport) string { return s.Tags }},
+	{"RESOLUTION", func(s gamma.Sport) string { return s.Resolution }},
+}
+
+var seriesColumns = []column[gamma.Series]{
+	{"ID", func(s gamma.Series) string { return s.ID }},
+	{"TITLE", func(s gamma.Series) string { return s.Title }},
+	{"SLUG", func(s gamma.Series) string { return s.Slug }},
+	{"TYPE", func(s gamma.Series) string { return s.SeriesType }},
+	{"RECURRENCE", func(s gamma.Series) string { return s.Recurrence }},
+	{"ACTIVE", func(s gamma.Series) string { return strconv.FormatBool(s.Active) }},
+}
@@ -0,0 +1,257 @@
+// gammago/cmd/gammago/watch.go
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// ANSI escape codes for the refreshing view
+const (
+	ansiClear = "\033[H\033[2J"
+	ansiGreen = "\033[32m"
+	ansiRed   = "\033[31m"
+	ansiReset = "\033[0m"
+)
+
+// watchRow is the part of a market shown by watch
+type watchRow struct {
+	ID         string
+	Question   string
+	Outcomes   []string
+	Prices     []float64
+	Volume24hr float64
+	Liquidity  float64
+	Spread     float64
+}
+
+func watch(args []string, out io.Writer) error {
+	fs := newBaseFlagSet("watch", "", out)
+	var marketIDs stringList
+	fs.Var(&marketIDs, "markets", "market IDs to watch (repeatable or comma separated)")
+	event := fs.String("event", "", "event ID or slug whose markets to watch")
+	interval := fs.Duration("interval", 10*time.Second, "poll interval")
+	count := fs.Int("count", 0, "stop after this many polls (0 = until interrupted)")
+	noColor := fs.Bool("no-color", false, "disable change highlighting and screen clearing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(marketIDs) == 0 && *event == "" {
+		return errors.New("watch needs -markets or -event")
+	}
+	if *interval <= 0 {
+		return errors.New("-interval must be positive")
+	}
+
+	ids := make([]int, 0, len(marketIDs))
+	for _, s := range marketIDs {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid market ID %q", s)
+		}
+		ids = append(ids, id)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	var prev map[string]watchRow
+	for poll := 1; ; poll++ {
+		markets, err := fetchWatched(ctx, ids, *event)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			// keep the last view and try again on the next tick
+			fmt.Fprintf(out, "%s  poll failed, retrying in %s: %v\n", time.Now().Format("15:04:05"), *interval, err)
+		default:
+			rows := rowsFromMarkets(markets)
+			if !*noColor {
+				fmt.Fprint(out, ansiClear)
+			}
+			renderWatch(out, rows, prev, time.Now(), !*noColor)
+
+			prev = make(map[string]watchRow, len(rows))
+			for _, r := range rows {
+				prev[r.ID] = r
+			}
+		}
+
+		if *count > 0 && poll >= *count {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetchWatched gets the listed markets plus every market of the event
+func fetchWatched(ctx context.Context, ids []int, event string) ([]gamma.Market, error) {
+	var markets []gamma.Market
+	for _, id := range ids {
+		m, err := gamma.GetMarketByIDCtx(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("market %d: %w", id, err)
+		}
+		markets = append(markets, m...)
+	}
+
+	if event != "" {
+		var e gamma.Event
+		var err error
+		if _, convErr := strconv.Atoi(event); convErr == nil {
+			e, err = gamma.GetEventByIDCtx(ctx, event)
+		} else {
+			e, err = gamma.GetEventBySlugCtx(ctx, event)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", event, err)
+		}
+		markets = append(markets, e.Markets...)
+	}
+
+	return markets, nil
+}
+
+func rowsFromMarkets(markets []gamma.Market) []watchRow {
+	rows := make([]watchRow, 0, len(markets))
+	for _, m := range markets {
+		r := watchRow{
+			ID:         m.ID,
+			Question:   m.Question,
+			Volume24hr: m.Volume24hr,
+			Spread:     m.Spread,
+		}
+		r.Liquidity, _ = strconv.ParseFloat(m.Liquidity, 64)
+
+		// Gamma encodes both lists as JSON strings inside the JSON
+		json.Unmarshal([]byte(m.Outcomes), &r.Outcomes)
+		var prices []string
+		json.Unmarshal([]byte(m.OutcomePrices), &prices)
+		for _, p := range prices {
+			f, _ := strconv.ParseFloat(p, 64)
+			r.Prices = append(r.Prices, f)
+		}
+
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+// watchCell is a table cell and which way its value moved since the last poll
+type watchCell struct {
+	text string
+	dir  int
+}
+
+func newWatchCell(text string, cur, prev float64, seen bool) watchCell {
+	c := watchCell{text: text}
+	switch {
+	case !seen:
+	case cur > prev:
+		c.dir = 1
+	case cur < prev:
+		c.dir = -1
+	}
+	return c
+}
+
+// width is the number of visible runes once highlighted
+func (c watchCell) width(color bool) int {
+	n := len([]rune(c.text))
+	if c.dir != 0 && !color {
+		n += 2 // arrow suffix
+	}
+	return n
+}
+
+// renderWatch prints one poll, highlighting values that moved since prev
+// Columns are padded by hand because tabwriter counts escape codes as width
+func renderWatch(out io.Writer, rows []watchRow, prev map[string]watchRow, at time.Time, color bool) {
+	fmt.Fprintf(out, "gammago watch  %s  (%d markets)\n\n", at.Format("2006-01-02 15:04:05"), len(rows))
+
+	table := [][]watchCell{{
+		{text: "ID"}, {text: "QUESTION"}, {text: "PRICES"}, {text: "VOL 24H"}, {text: "LIQUIDITY"}, {text: "SPREAD"},
+	}}
+
+	for _, r := range rows {
+		p, seen := prev[r.ID]
+
+		// prices share one cell, so it moves with the first outcome (usually Yes)
+		prices := make([]string, len(r.Prices))
+		for i, price := range r.Prices {
+			label := strconv.Itoa(i)
+			if i < len(r.Outcomes) {
+				label = r.Outcomes[i]
+			}
+			prices[i] = fmt.Sprintf("%s %.3f", label, price)
+		}
+		var cur, last float64
+		if len(r.Prices) > 0 && len(p.Prices) > 0 {
+			cur, last = r.Prices[0], p.Prices[0]
+		}
+
+		table = append(table, []watchCell{
+			{text: r.ID},
+			{text: truncate(r.Question)},
+			newWatchCell(strings.Join(prices, "  "), cur, last, seen),
+			newWatchCell(formatMoney(r.Volume24hr), r.Volume24hr, p.Volume24hr, seen),
+			newWatchCell(formatMoney(r.Liquidity), r.Liquidity, p.Liquidity, seen),
+			newWatchCell(fmt.Sprintf("%.3f", r.Spread), r.Spread, p.Spread, seen),
+		})
+	}
+
+	widths := make([]int, len(table[0]))
+	for _, row := range table {
+		for i, c := range row {
+			widths[i] = max(widths[i], c.width(color))
+		}
+	}
+
+	for _, row := range table {
+		var sb strings.Builder
+		for i, c := range row {
+			text := highlight(c, color)
+			if i < len(row)-1 {
+				text += strings.Repeat(" ", widths[i]-c.width(color)+2)
+			}
+			sb.WriteString(text)
+		}
+		fmt.Fprintln(out, strings.TrimRight(sb.String(), " "))
+	}
+}
+
+// highlight marks a value that changed: green when up, red when down
+// Without color an arrow is appended instead
+func highlight(c watchCell, color bool) string {
+	switch {
+	case c.dir > 0 && color:
+		return ansiGreen + c.text + ansiReset
+	case c.dir < 0 && color:
+		return ansiRed + c.text + ansiReset
+	case c.dir > 0:
+		return c.text + " ↑"
+	case c.dir < 0:
+		return c.text + " ↓"
+	}
+	return c.text
+}
@@ -0,0 +1,120 @@
+// gammago/cmd/gammago/watch_test.go
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func TestWatch(t *testing.T) {
+	markets := []gamma.Market{{
+		ID:            "501",
+		Question:      "Will Lakers win?",
+		Outcomes:      `["Yes", "No"]`,
+		OutcomePrices: `["0.45", "0.55"]`,
+		Volume24hr:    1000,
+		Liquidity:     "2500.5",
+		Spread:        0.01,
+	}}
+
+	t.Run("rowsFromMarkets parses encoded outcomes and prices", func(t *testing.T) {
+		rows := rowsFromMarkets(markets)
+		if len(rows) != 1 {
+			t.Fatalf("got %d rows, want 1", len(rows))
+		}
+		r := rows[0]
+		if strings.Join(r.Outcomes, ",") != "Yes,No" {
+			t.Errorf("outcomes = %v", r.Outcomes)
+		}
+		if len(r.Prices) != 2 || r.Prices[0] != 0.45 || r.Prices[1] != 0.55 {
+			t.Errorf("prices = %v", r.Prices)
+		}
+		if r.Liquidity != 2500.5 {
+			t.Errorf("liquidity = %v", r.Liquidity)
+		}
+	})
+
+	t.Run("renderWatch marks changes since the previous poll", func(t *testing.T) {
+		at := time.Date(2026, 1, 15, 19, 30, 0, 0, time.UTC)
+		first := rowsFromMarkets(markets)
+
+		var buf bytes.Buffer
+		renderWatch(&buf, first, nil, at, false)
+		if strings.ContainsAny(buf.String(), "↑↓") {
+			t.Errorf("first poll should not mark changes:\n%s", buf.String())
+		}
+
+		prev := map[string]watchRow{"501": first[0]}
+		next := first[0]
+		next.Prices = []float64{0.50, 0.50}
+		next.Volume24hr = 900
+
+		buf.Reset()
+		renderWatch(&buf, []watchRow{next}, prev, at, false)
+		out := buf.String()
+		if !strings.Contains(out, "Yes 0.500  No 0.500 ↑") {
+			t.Errorf("price rise not marked:\n%s", out)
+		}
+		if !strings.Contains(out, "900.00 ↓") {
+			t.Errorf("volume drop not marked:\n%s", out)
+		}
+
+		buf.Reset()
+		renderWatch(&buf, []watchRow{next}, prev, at, true)
+		if !strings.Contains(buf.String(), ansiGreen) || !strings.Contains(buf.String(), ansiRed) {
+			t.Errorf("color output missing escape codes:\n%q", buf.String())
+		}
+	})
+
+	t.Run("columns line up", func(t *testing.T) {
+		rows := rowsFromMarkets(markets)
+		var buf bytes.Buffer
+		renderWatch(&buf, rows, nil, time.Now(), false)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		header, row := lines[len(lines)-2], lines[len(lines)-1]
+		if strings.Index(header, "PRICES") != strings.Index(row, "Yes") {
+			t.Errorf("PRICES column misaligned:\n%s\n%s", header, row)
+		}
+	})
+
+	t.Run("flag validation", func(t *testing.T) {
+		if err := watch(nil, &bytes.Buffer{}); err == nil {
+			t.Error("expected error without -markets or -event")
+		}
+		if err := watch([]string{"-markets", "x"}, &bytes.Buffer{}); err == nil {
+			t.Error("expected error for non-numeric market ID")
+		}
+	})
+
+	t.Run("a failed poll is retried on the next tick", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				http.Error(w, "not yet", http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`[{"id":"501","question":"Will Lakers win?","outcomes":"[\"Yes\",\"No\"]","outcomePrices":"[\"0.45\",\"0.55\"]"}]`))
+		}))
+		defer srv.Close()
+		gamma.SetBaseURL(srv.URL)
+		defer gamma.SetBaseURL("")
+
+		var buf bytes.Buffer
+		if err := watch([]string{"-markets", "501", "-interval", "10ms", "-count", "2", "-no-color"}, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "poll failed, retrying") || !strings.Contains(out, "Will Lakers win?") {
+			t.Errorf("expected a logged failure then a table:\n%s", out)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+// gammago/cmd/gammaproxy/cache.go
+
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cache is a size-bounded LRU of response bodies with per-entry expiry
+type cache struct {
+	mu        sync.Mutex
+	max       int
+	order     *list.List // front is most recently used
+	entries   map[string]*list.Element
+	evictions int64
+}
+
+type entry struct {
+	key     string
+	body    []byte
+	expires time.Time
+}
+
+func newCache(size int) *cache {
+	return &cache{max: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *cache) get(key string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !now.Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.body, true
+}
+
+func (c *cache) put(key string, body []byte, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.body, e.expires = body, expires
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, body: body, expires: expires})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+		c.evictions++
+	}
+}
+
+func (c *cache) stats() (entries int, evictions int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len(), c.evictions
+}
@@ -0,0 +1,55 @@
+// gammago/cmd/gammaproxy/main.go
+
+// Command gammaproxy serves the Gamma REST API from a local cache
+//
+// Services point their base URL at the proxy instead of
+// gamma-api.polymarket.com. Misses are fetched through the gammago request
+// loop, identical concurrent requests share one upstream call, and
+// successful responses are cached for -ttl
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func main() {
+	fs := flag.NewFlagSet("gammaproxy", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "listen address")
+	ttl := fs.Duration("ttl", 30*time.Second, "how long successful responses are cached")
+	maxEntries := fs.Int("max-entries", 10000, "cache size in responses, least recently used are evicted")
+	maxBody := fs.Int64("max-body", 64<<20, "largest upstream body in bytes (0 = no limit)")
+	upstream := fs.String("upstream", gamma.BASE_URL, "Gamma API base URL")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+	if *ttl <= 0 || *maxEntries <= 0 {
+		fmt.Fprintln(os.Stderr, "gammaproxy: -ttl and -max-entries must be positive")
+		os.Exit(2)
+	}
+
+	gamma.SetBaseURL(*upstream)
+	gamma.SetMaxResponseSize(*maxBody)
+	metrics := gamma.NewMetrics()
+	gamma.SetMetrics(metrics)
+
+	p := newProxy(gamma.GetRaw, *ttl, *maxEntries)
+	mux := http.NewServeMux()
+	mux.Handle("/", p)
+	mux.HandleFunc(statsPath, p.serveStats)
+	mux.Handle(metricsPath, metrics.Handler())
+
+	log.Printf("gammaproxy: serving %s on %s (ttl %s)", *upstream, *addr, *ttl)
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(srv.ListenAndServe())
+}
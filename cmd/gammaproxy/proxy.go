@@ -0,0 +1,194 @@
+// gammago/cmd/gammaproxy/proxy.go
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// Paths the proxy serves itself rather than forwarding
+const (
+	statsPath   = "/_proxy/stats"
+	metricsPath = "/_proxy/metrics"
+)
+
+// X-Cache header values
+const (
+	cacheHit       = "HIT"
+	cacheMiss      = "MISS"
+	cacheCoalesced = "COALESCED"
+)
+
+var errFetchPanicked = errors.New("upstream fetch panicked")
+
+// fetchFunc gets one upstream path; gamma.GetRaw in production
+type fetchFunc func(ctx context.Context, path string, params url.Values) ([]byte, error)
+
+type proxy struct {
+	fetch fetchFunc
+	ttl   time.Duration
+	cache *cache
+	now   func() time.Time
+
+	mu       sync.Mutex
+	inflight map[string]*call
+
+	requests       atomic.Int64
+	hits           atomic.Int64
+	misses         atomic.Int64
+	coalesced      atomic.Int64
+	upstreamErrors atomic.Int64
+}
+
+// call is one upstream fetch shared by every request for the same key
+type call struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// proxyStats is the JSON served at statsPath
+type proxyStats struct {
+	Requests       int64 `json:"requests"`
+	Hits           int64 `json:"hits"`
+	Misses         int64 `json:"misses"`
+	Coalesced      int64 `json:"coalesced"`
+	UpstreamErrors int64 `json:"upstream_errors"`
+	Entries        int   `json:"entries"`
+	Evictions      int64 `json:"evictions"`
+}
+
+func newProxy(fetch fetchFunc, ttl time.Duration, maxEntries int) *proxy {
+	return &proxy{
+		fetch:    fetch,
+		ttl:      ttl,
+		cache:    newCache(maxEntries),
+		now:      time.Now,
+		inflight: make(map[string]*call),
+	}
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	p.requests.Add(1)
+
+	// Encode sorts by key, so parameter order doesn't split the cache
+	params := r.URL.Query()
+	key := r.URL.Path + "?" + params.Encode()
+
+	if body, ok := p.cache.get(key, p.now()); ok {
+		p.hits.Add(1)
+		writeBody(w, r, cacheHit, body)
+		return
+	}
+
+	body, shared, err := p.do(r.Context(), key, r.URL.Path, params)
+	if shared {
+		p.coalesced.Add(1)
+	} else {
+		p.misses.Add(1)
+	}
+	if err != nil {
+		var se *gamma.StatusError
+		switch {
+		case errors.As(err, &se):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(se.StatusCode)
+			w.Write(se.Body)
+		case r.Context().Err() != nil:
+			// client went away, nothing to write to
+		default:
+			writeError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	status := cacheMiss
+	if shared {
+		status = cacheCoalesced
+	}
+	writeBody(w, r, status, body)
+}
+
+// do fetches key once no matter how many requests ask for it concurrently
+// shared reports whether this request waited on another's fetch
+func (p *proxy) do(ctx context.Context, key, path string, params url.Values) (body []byte, shared bool, err error) {
+	p.mu.Lock()
+	if c, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.body, true, c.err
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+	// errFetchPanicked stays set only if fetch never returns
+	c := &call{done: make(chan struct{}), err: errFetchPanicked}
+	p.inflight[key] = c
+	p.mu.Unlock()
+
+	// release waiters and the key even if fetch panics
+	defer func() {
+		p.mu.Lock()
+		delete(p.inflight, key)
+		p.mu.Unlock()
+		close(c.done)
+	}()
+
+	// the fetch outlives this request so waiters aren't cancelled with it
+	c.body, c.err = p.fetch(context.WithoutCancel(ctx), path, params)
+	if c.err != nil {
+		p.upstreamErrors.Add(1)
+	} else {
+		p.cache.put(key, c.body, p.now().Add(p.ttl))
+	}
+
+	return c.body, false, c.err
+}
+
+func (p *proxy) stats() proxyStats {
+	entries, evictions := p.cache.stats()
+	return proxyStats{
+		Requests:       p.requests.Load(),
+		Hits:           p.hits.Load(),
+		Misses:         p.misses.Load(),
+		Coalesced:      p.coalesced.Load(),
+		UpstreamErrors: p.upstreamErrors.Load(),
+		Entries:        entries,
+		Evictions:      evictions,
+	}
+}
+
+func (p *proxy) serveStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p.stats())
+}
+
+func writeBody(w http.ResponseWriter, r *http.Request, cacheStatus string, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Cache", cacheStatus)
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
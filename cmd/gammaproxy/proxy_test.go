@@ -0,0 +1,281 @@
+// gammago/cmd/gammaproxy/proxy_test.go
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func get(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestProxy(t *testing.T) {
+	t.Run("miss then hit, parameter order ignored", func(t *testing.T) {
+		var calls atomic.Int32
+		p := newProxy(func(ctx context.Context, path string, params url.Values) ([]byte, error) {
+			calls.Add(1)
+			return []byte(`[{"path":"` + path + `","q":"` + params.Encode() + `"}]`), nil
+		}, time.Minute, 10)
+
+		rec := get(t, p, "/markets?limit=5&id=1")
+		if rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != cacheMiss {
+			t.Fatalf("Expected 200 MISS, got %d %s", rec.Code, rec.Header().Get("X-Cache"))
+		}
+		if rec.Body.String() != `[{"path":"/markets","q":"id=1&limit=5"}]` {
+			t.Errorf("Unexpected body %s", rec.Body)
+		}
+
+		rec = get(t, p, "/markets?id=1&limit=5")
+		if rec.Header().Get("X-Cache") != cacheHit {
+			t.Errorf("Expected HIT, got %s", rec.Header().Get("X-Cache"))
+		}
+		if calls.Load() != 1 {
+			t.Errorf("Expected 1 upstream call, got %d", calls.Load())
+		}
+	})
+
+	t.Run("entries expire after the ttl", func(t *testing.T) {
+		var calls atomic.Int32
+		p := newProxy(func(ctx context.Context, path string, params url.Values) ([]byte, error) {
+			calls.Add(1)
+			return []byte(`{}`), nil
+		}, time.Minute, 10)
+		now := time.Now()
+		p.now = func() time.Time { return now }
+
+		get(t, p, "/tags")
+		now = now.Add(59 * time.Second)
+		get(t, p, "/tags")
+		now = now.Add(time.Second)
+		get(t, p, "/tags")
+		if calls.Load() != 2 {
+			t.Errorf("Expected 2 upstream calls, got %d", calls.Load())
+		}
+	})
+
+	t.Run("identical requests are coalesced", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		p := newProxy(func(ctx context.Context, path string, params url.Values) ([]byte, error) {
+			calls.Add(1)
+			<-release
+			return []byte(`{"id":"1"}`), nil
+		}, time.Minute, 10)
+
+		const n = 10
+		var wg sync.WaitGroup
+		recs := make([]*httptest.ResponseRecorder, n)
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				recs[i] = get(t, p, "/events/1")
+			}()
+		}
+		// wait until every request is either fetching or waiting on the fetch
+		for p.requests.Load() < n {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond) // let the waiter reach the shared call
+		close(release)
+		wg.Wait()
+
+		if calls.Load() != 1 {
+			t.Errorf("Expected 1 upstream call, got %d", calls.Load())
+		}
+		statuses := map[string]int{}
+		for _, rec := range recs {
+			if rec.Body.String() != `{"id":"1"}` {
+				t.Errorf("Unexpected body %s", rec.Body)
+			}
+			statuses[rec.Header().Get("X-Cache")]++
+		}
+		if statuses[cacheMiss] != 1 || statuses[cacheCoalesced] != n-1 {
+			t.Errorf("Expected 1 MISS and %d COALESCED, got %v", n-1, statuses)
+		}
+		if s := p.stats(); s.Coalesced != n-1 || s.Misses != 1 {
+			t.Errorf("Unexpected stats %+v", s)
+		}
+	})
+
+	t.Run("upstream status is passed through and not cached", func(t *testing.T) {
+		var calls atomic.Int32
+		p := newProxy(func(ctx context.Context, path string, params url.Values) ([]byte, error) {
+			calls.Add(1)
+			return nil, &gamma.StatusError{StatusCode: http.StatusNotFound, Body: []byte(`{"error":"not found"}`)}
+		}, time.Minute, 10)
+
+		for range 2 {
+			rec := get(t, p, "/events/404")
+			if rec.Code != http.StatusNotFound || rec.Body.String() != `{"error":"not found"}` {
+				t.Errorf("Expected upstream 404, got %d %s", rec.Code, rec.Body)
+			}
+		}
+		if calls.Load() != 2 {
+			t.Errorf("Expected errors not to be cached, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("transport errors are 502", func(t *testing.T) {
+		p := newProxy(func(ctx context.Context, path string, params url.Values) ([]byte, error) {
+			return nil, errors.New("connection refused")
+		}, time.Minute, 10)
+
+		rec := get(t, p, "/markets")
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected 502, got %d", rec.Code)
+		}
+		if s := p.stats(); s.UpstreamErrors != 1 {
+			t.Errorf("Expected 1 upstream error, got %+v", s)
+		}
+	})
+
+	t.Run("a panicking fetch releases the key", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		p := newProxy(func(ctx context.Context, path string, params url.Values) ([]byte, error) {
+			if calls.Add(1) == 1 {
+				<-release
+				panic("boom")
+			}
+			return []byte(`[]`), nil
+		}, time.Minute, 10)
+
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() { panicked <- recover() }()
+			get(t, p, "/markets?id=1")
+		}()
+
+		// a second request waits on the doomed fetch
+		waiter := make(chan error, 1)
+		for {
+			p.mu.Lock()
+			_, started := p.inflight["/markets?id=1"]
+			p.mu.Unlock()
+			if started {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		go func() {
+			_, _, err := p.do(t.Context(), "/markets?id=1", "/markets", url.Values{"id": {"1"}})
+			waiter <- err
+		}()
+		time.Sleep(50 * time.Millisecond) // let the waiter reach the shared call
+		close(release)
+
+		if r := <-panicked; r != "boom" {
+			t.Errorf("Expected the panic to reach the handler, got %v", r)
+		}
+		if err := <-waiter; !errors.Is(err, errFetchPanicked) {
+			t.Errorf("Expected errFetchPanicked for the waiter, got %v", err)
+		}
+
+		done := make(chan *httptest.ResponseRecorder, 1)
+		go func() { done <- get(t, p, "/markets?id=1") }()
+		select {
+		case rec := <-done:
+			if rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != cacheMiss {
+				t.Errorf("Expected a fresh 200 MISS, got %d %s", rec.Code, rec.Header().Get("X-Cache"))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Request after the panic hung")
+		}
+	})
+
+	t.Run("only GET and HEAD", func(t *testing.T) {
+		p := newProxy(nil, time.Minute, 10)
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/markets", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected 405, got %d", rec.Code)
+		}
+	})
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache(2)
+	now := time.Now()
+	exp := now.Add(time.Minute)
+
+	c.put("a", []byte("a"), exp)
+	c.put("b", []byte("b"), exp)
+	c.get("a", now) // a is now more recent than b
+	c.put("c", []byte("c"), exp)
+
+	if _, ok := c.get("b", now); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, ok := c.get("a", now); !ok {
+		t.Error("Expected recently used entry to stay")
+	}
+	if entries, evictions := c.stats(); entries != 2 || evictions != 1 {
+		t.Errorf("Expected 2 entries and 1 eviction, got %d and %d", entries, evictions)
+	}
+}
+
+// TestThroughLibrary serves through the real gamma.GetRaw from a fake upstream
+func TestThroughLibrary(t *testing.T) {
+	var calls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`[{"id":"7","label":"Sports","slug":"sports"}]`))
+	}))
+	defer upstream.Close()
+	gamma.SetBaseURL(upstream.URL)
+	defer gamma.SetBaseURL("")
+
+	p := newProxy(gamma.GetRaw, time.Minute, 10)
+	mux := http.NewServeMux()
+	mux.Handle("/", p)
+	mux.HandleFunc(statsPath, p.serveStats)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for range 3 {
+		resp, err := http.Get(srv.URL + "/tags?limit=10&offset=0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		var tags []gamma.Tag
+		if err := json.Unmarshal(body, &tags); err != nil || len(tags) != 1 || tags[0].Label != "Sports" {
+			t.Errorf("Unexpected body %s", body)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", calls.Load())
+	}
+
+	resp, err := http.Get(srv.URL + statsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	var s proxyStats
+	if err := json.Unmarshal(body, &s); err != nil {
+		t.Fatalf("Bad stats %s: %v", body, err)
+	}
+	if s.Requests != 3 || s.Hits != 2 || s.Misses != 1 || s.Entries != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
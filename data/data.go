@@ -0,0 +1,206 @@
+// gammago/data/data.go
+
+// Package data reads positions, trades and holders from Polymarket's Data API
+//
+// Requests go through gammago.GetRawFrom, so the HTTP client, retries,
+// middleware, hooks, logging, metrics and tracing configured on the main
+// package apply here too. A non-2xx response is a *gammago.StatusError
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+const BaseURL = "https://data-api.polymarket.com"
+
+var (
+	configMu sync.RWMutex
+	baseURL  = BaseURL
+)
+
+// SetBaseURL points requests at another Data API compatible host, such as
+// a test server. Pass "" to go back to BaseURL
+func SetBaseURL(u string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if u == "" {
+		u = BaseURL
+	}
+	baseURL = u
+}
+
+func getBaseURL() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return baseURL
+}
+
+// Position is a user's holding of one outcome token
+type Position struct {
+	ProxyWallet        string  `json:"proxyWallet"`
+	Asset              string  `json:"asset"`
+	ConditionID        string  `json:"conditionId"`
+	Size               float64 `json:"size"`
+	AvgPrice           float64 `json:"avgPrice"`
+	InitialValue       float64 `json:"initialValue"`
+	CurrentValue       float64 `json:"currentValue"`
+	CashPnl            float64 `json:"cashPnl"`
+	PercentPnl         float64 `json:"percentPnl"`
+	TotalBought        float64 `json:"totalBought"`
+	RealizedPnl        float64 `json:"realizedPnl"`
+	PercentRealizedPnl float64 `json:"percentRealizedPnl"`
+	CurPrice           float64 `json:"curPrice"`
+	Redeemable         bool    `json:"redeemable"`
+	Mergeable          bool    `json:"mergeable"`
+	Title              string  `json:"title"`
+	Slug               string  `json:"slug"`
+	Icon               string  `json:"icon"`
+	EventSlug          string  `json:"eventSlug"`
+	Outcome            string  `json:"outcome"`
+	OutcomeIndex       int     `json:"outcomeIndex"`
+	OppositeOutcome    string  `json:"oppositeOutcome"`
+	OppositeAsset      string  `json:"oppositeAsset"`
+	EndDate            string  `json:"endDate"`
+	NegativeRisk       bool    `json:"negativeRisk"`
+}
+
+// Trade is one fill
+type Trade struct {
+	ProxyWallet     string  `json:"proxyWallet"`
+	Side            string  `json:"side"`
+	Asset           string  `json:"asset"`
+	ConditionID     string  `json:"conditionId"`
+	Size            float64 `json:"size"`
+	Price           float64 `json:"price"`
+	Timestamp       int64   `json:"timestamp"`
+	Title           string  `json:"title"`
+	Slug            string  `json:"slug"`
+	Icon            string  `json:"icon"`
+	EventSlug       string  `json:"eventSlug"`
+	Outcome         string  `json:"outcome"`
+	OutcomeIndex    int     `json:"outcomeIndex"`
+	Name            string  `json:"name"`
+	Pseudonym       string  `json:"pseudonym"`
+	TransactionHash string  `json:"transactionHash"`
+}
+
+// Time is Timestamp as a time
+func (t Trade) Time() time.Time {
+	return time.Unix(t.Timestamp, 0).UTC()
+}
+
+// Holder is a wallet holding one outcome token of a market
+type Holder struct {
+	ProxyWallet  string  `json:"proxyWallet"`
+	Asset        string  `json:"asset"`
+	Amount       float64 `json:"amount"`
+	OutcomeIndex int     `json:"outcomeIndex"`
+	Name         string  `json:"name"`
+	Pseudonym    string  `json:"pseudonym"`
+	Bio          string  `json:"bio"`
+	ProfileImage string  `json:"profileImage"`
+}
+
+// TokenHolders is the top holders of one outcome token
+type TokenHolders struct {
+	Token   string   `json:"token"`
+	Holders []Holder `json:"holders"`
+}
+
+// PositionParams filters GetPositions
+// Markets are condition IDs; a zero Limit leaves it to the API
+type PositionParams struct {
+	Markets       []string
+	SizeThreshold float64
+	Redeemable    bool
+	Limit         int
+	Offset        int
+}
+
+// TradeParams filters GetTrades
+// Set User, Markets or both. A zero Limit leaves it to the API
+type TradeParams struct {
+	User      string
+	Markets   []string
+	Side      string // "BUY" or "SELL", empty for both
+	TakerOnly bool
+	Limit     int
+	Offset    int
+}
+
+// GetPositions gets the positions of the wallet user
+func GetPositions(ctx context.Context, user string, p PositionParams) ([]Position, error) {
+	params := url.Values{"user": {user}}
+	addMarkets(params, p.Markets)
+	if p.SizeThreshold > 0 {
+		params.Set("sizeThreshold", strconv.FormatFloat(p.SizeThreshold, 'f', -1, 64))
+	}
+	if p.Redeemable {
+		params.Set("redeemable", "true")
+	}
+	addPage(params, p.Limit, p.Offset)
+	return get[[]Position](ctx, "/positions", params)
+}
+
+// GetTrades gets trades, newest first
+func GetTrades(ctx context.Context, p TradeParams) ([]Trade, error) {
+	params := url.Values{}
+	if p.User != "" {
+		params.Set("user", p.User)
+	}
+	addMarkets(params, p.Markets)
+	if p.Side != "" {
+		params.Set("side", p.Side)
+	}
+	if p.TakerOnly {
+		params.Set("takerOnly", "true")
+	}
+	addPage(params, p.Limit, p.Offset)
+	return get[[]Trade](ctx, "/trades", params)
+}
+
+// GetHolders gets the top holders of each outcome token of the market
+// with conditionID. A zero limit leaves it to the API
+func GetHolders(ctx context.Context, conditionID string, limit int) ([]TokenHolders, error) {
+	params := url.Values{"market": {conditionID}}
+	addPage(params, limit, 0)
+	return get[[]TokenHolders](ctx, "/holders", params)
+}
+
+// the Data API takes several condition IDs as one comma-separated value
+func addMarkets(params url.Values, markets []string) {
+	if len(markets) == 0 {
+		return
+	}
+	params.Set("market", strings.Join(markets, ","))
+}
+
+func addPage(params url.Values, limit, offset int) {
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+}
+
+func get[T any](ctx context.Context, path string, params url.Values) (T, error) {
+	var v T
+	body, err := gamma.GetRawFrom(ctx, getBaseURL(), path, params)
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return v, fmt.Errorf("data: decoding %s: %w", path, err)
+	}
+	return v, nil
+}
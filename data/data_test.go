@@ -0,0 +1,212 @@
+// gammago/data/data_test.go
+
+package data
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+type fakeAPIs struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (f *fakeAPIs) last() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.queries[len(f.queries)-1]
+}
+
+// fake serves the Data API and Gamma from one test server
+func fake(t *testing.T) *fakeAPIs {
+	t.Helper()
+	f := &fakeAPIs{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.queries = append(f.queries, r.URL.Path+"?"+r.URL.RawQuery)
+		f.mu.Unlock()
+		switch r.URL.Path {
+		case "/positions":
+			w.Write([]byte(`[
+				{"proxyWallet":"0xabc","asset":"111","conditionId":"0xc1","size":120.5,"avgPrice":0.4,"curPrice":0.55,"cashPnl":18.07,"outcome":"Yes","outcomeIndex":0,"title":"Will A win?"},
+				{"proxyWallet":"0xabc","asset":"222","conditionId":"0xc2","size":10,"avgPrice":0.9,"curPrice":1,"redeemable":true,"outcome":"No","outcomeIndex":1}
+			]`))
+		case "/trades":
+			w.Write([]byte(`[{"proxyWallet":"0xabc","side":"BUY","asset":"111","conditionId":"0xc1","size":50,"price":0.42,"timestamp":1760000000,"transactionHash":"0xt1"}]`))
+		case "/holders":
+			w.Write([]byte(`[{"token":"111","holders":[{"proxyWallet":"0xabc","asset":"111","amount":120.5,"outcomeIndex":0,"name":"alice"}]},
+				{"token":"222","holders":[]}]`))
+		case "/value":
+			w.Write([]byte(`[{"user":"` + r.URL.Query().Get("user") + `","value":1523.25}]`))
+		case "/traded":
+			w.Write([]byte(`{"user":"` + r.URL.Query().Get("user") + `","traded":42}`))
+		case "/public-profile":
+			w.Write([]byte(`{"proxyWallet":"` + r.URL.Query().Get("address") + `","pseudonym":"Bright-Owl"}`))
+		case "/markets":
+			var markets []string
+			for _, id := range r.URL.Query()["condition_ids"] {
+				if id == "0xc1" {
+					markets = append(markets, `{"id":"501","conditionId":"0xc1","question":"Will A win?"}`)
+				}
+			}
+			w.Write([]byte("[" + strings.Join(markets, ",") + "]"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad request"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	SetBaseURL(srv.URL)
+	gamma.SetBaseURL(srv.URL)
+	t.Cleanup(func() {
+		SetBaseURL("")
+		gamma.SetBaseURL("")
+	})
+	return f
+}
+
+func TestGetPositions(t *testing.T) {
+	f := fake(t)
+
+	positions, err := GetPositions(t.Context(), "0xabc", PositionParams{Markets: []string{"0xc1", "0xc2"}, SizeThreshold: 1.5, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetPositions failed: %v", err)
+	}
+	if len(positions) != 2 || positions[0].Size != 120.5 || positions[0].ConditionID != "0xc1" || !positions[1].Redeemable {
+		t.Errorf("Unexpected positions %+v", positions)
+	}
+	want := "/positions?limit=10&market=0xc1%2C0xc2&sizeThreshold=1.5&user=0xabc"
+	if got := f.last(); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestGetTradesAndHolders(t *testing.T) {
+	f := fake(t)
+
+	trades, err := GetTrades(t.Context(), TradeParams{User: "0xabc", Side: "BUY", TakerOnly: true})
+	if err != nil {
+		t.Fatalf("GetTrades failed: %v", err)
+	}
+	if len(trades) != 1 || trades[0].Price != 0.42 || trades[0].Time().Unix() != 1760000000 {
+		t.Errorf("Unexpected trades %+v", trades)
+	}
+	if got, want := f.last(), "/trades?side=BUY&takerOnly=true&user=0xabc"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	holders, err := GetHolders(t.Context(), "0xc1", 5)
+	if err != nil {
+		t.Fatalf("GetHolders failed: %v", err)
+	}
+	if len(holders) != 2 || holders[0].Token != "111" || holders[0].Holders[0].Name != "alice" || len(holders[1].Holders) != 0 {
+		t.Errorf("Unexpected holders %+v", holders)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	f := fake(t)
+
+	joined, err := GetPositionsWithMarkets(t.Context(), "0xabc", PositionParams{})
+	if err != nil {
+		t.Fatalf("GetPositionsWithMarkets failed: %v", err)
+	}
+	if len(joined) != 2 || joined[0].Market == nil || joined[0].Market.ID != "501" || joined[0].Size != 120.5 {
+		t.Errorf("Expected first position joined to market 501, got %+v", joined[0])
+	}
+	if joined[1].Market != nil {
+		t.Errorf("Expected no market for an unknown condition ID, got %+v", joined[1].Market)
+	}
+	if got := f.last(); got != "/markets?condition_ids=0xc1&condition_ids=0xc2&limit=2" {
+		t.Errorf("Unexpected Gamma query %s", got)
+	}
+
+	trades, err := GetTradesWithMarkets(t.Context(), TradeParams{Markets: []string{"0xc1"}})
+	if err != nil || len(trades) != 1 || trades[0].Market == nil || trades[0].Market.Question != "Will A win?" {
+		t.Errorf("Unexpected joined trades %+v %v", trades, err)
+	}
+
+	ix := IndexMarkets([]gamma.Market{{ID: "1", ConditionID: "0xc9"}})
+	if got := JoinTrades([]Trade{{ConditionID: "0xc9"}}, ix); got[0].Market == nil || got[0].Market.ID != "1" {
+		t.Errorf("Unexpected join %+v", got)
+	}
+}
+
+func TestGetMarketsChunks(t *testing.T) {
+	f := fake(t)
+
+	ids := []string{"", "0xc1", "0xc1"}
+	for i := range conditionIDsPerRequest {
+		ids = append(ids, "0xd"+string(rune('a'+i%26))+string(rune('a'+i/26)))
+	}
+	ix, err := GetMarkets(t.Context(), ids...)
+	if err != nil {
+		t.Fatalf("GetMarkets failed: %v", err)
+	}
+	if len(ix) != 1 || ix["0xc1"].ID != "501" {
+		t.Errorf("Unexpected index %+v", ix)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	markets := slices.DeleteFunc(slices.Clone(f.queries), func(q string) bool { return !strings.HasPrefix(q, "/markets") })
+	if len(markets) != 2 {
+		t.Errorf("Expected 51 IDs to take 2 requests, got %d", len(markets))
+	}
+}
+
+func TestGetMarketsContext(t *testing.T) {
+	f := fake(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := GetMarkets(ctx, "0xc1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	ix, err := GetMarkets(t.Context(), "")
+	if err != nil || len(ix) != 0 {
+		t.Errorf("Expected an empty index, got %v, %v", ix, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.queries) != 0 {
+		t.Errorf("Expected no requests, got %v", f.queries)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	fake(t)
+
+	_, err := get[[]Trade](t.Context(), "/nope", nil)
+	var se *gamma.StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a 400 StatusError, got %v", err)
+	}
+}
+
+func TestGetUserProfile(t *testing.T) {
+	fake(t)
+
+	const wallet = "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
+	p, err := GetUserProfile(t.Context(), wallet)
+	if err != nil {
+		t.Fatalf("GetUserProfile failed: %v", err)
+	}
+	if p.ProxyWallet != wallet || p.DisplayName() != "Bright-Owl" || p.Stats.Value != 1523.25 || p.Stats.MarketsTraded != 42 {
+		t.Errorf("Unexpected profile %+v", p)
+	}
+
+	if _, err := GetUserProfile(t.Context(), "alice"); err == nil {
+		t.Error("Expected an error for a malformed address")
+	}
+}
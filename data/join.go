@@ -0,0 +1,121 @@
+// gammago/data/join.go
+
+package data
+
+import (
+	"context"
+	"slices"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// conditionIDsPerRequest keeps Gamma request URLs a sensible length
+const conditionIDsPerRequest = 50
+
+// MarketIndex maps condition IDs to Gamma markets
+type MarketIndex map[string]gamma.Market
+
+// IndexMarkets indexes markets by ConditionID
+func IndexMarkets(markets []gamma.Market) MarketIndex {
+	ix := make(MarketIndex, len(markets))
+	for _, m := range markets {
+		ix[m.ConditionID] = m
+	}
+	return ix
+}
+
+// GetMarkets gets the Gamma markets for conditionIDs
+// Duplicates and empty IDs are dropped; IDs Gamma doesn't know are missing
+// from the index
+func GetMarkets(ctx context.Context, conditionIDs ...string) (MarketIndex, error) {
+	ids := slices.Clone(conditionIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	ids = slices.DeleteFunc(ids, func(id string) bool { return id == "" })
+
+	ix := make(MarketIndex, len(ids))
+	for chunk := range slices.Chunk(ids, conditionIDsPerRequest) {
+		markets, err := gamma.GetMarketsByConditionIDsCtx(ctx, chunk...)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range markets {
+			ix[m.ConditionID] = m
+		}
+	}
+	return ix, nil
+}
+
+// MarketPosition is a position with its Gamma market
+// Market is nil when Gamma has no market for the condition ID
+type MarketPosition struct {
+	Position
+	Market *gamma.Market
+}
+
+// MarketTrade is a trade with its Gamma market
+// Market is nil when Gamma has no market for the condition ID
+type MarketTrade struct {
+	Trade
+	Market *gamma.Market
+}
+
+// JoinPositions pairs each position with its market in ix
+func JoinPositions(positions []Position, ix MarketIndex) []MarketPosition {
+	out := make([]MarketPosition, len(positions))
+	for i, p := range positions {
+		out[i] = MarketPosition{Position: p, Market: ix.lookup(p.ConditionID)}
+	}
+	return out
+}
+
+// JoinTrades pairs each trade with its market in ix
+func JoinTrades(trades []Trade, ix MarketIndex) []MarketTrade {
+	out := make([]MarketTrade, len(trades))
+	for i, t := range trades {
+		out[i] = MarketTrade{Trade: t, Market: ix.lookup(t.ConditionID)}
+	}
+	return out
+}
+
+// GetPositionsWithMarkets is GetPositions joined to Gamma markets
+func GetPositionsWithMarkets(ctx context.Context, user string, p PositionParams) ([]MarketPosition, error) {
+	positions, err := GetPositions(ctx, user, p)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(positions))
+	for i, pos := range positions {
+		ids[i] = pos.ConditionID
+	}
+	ix, err := GetMarkets(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	return JoinPositions(positions, ix), nil
+}
+
+// GetTradesWithMarkets is GetTrades joined to Gamma markets
+func GetTradesWithMarkets(ctx context.Context, p TradeParams) ([]MarketTrade, error) {
+	trades, err := GetTrades(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(trades))
+	for i, t := range trades {
+		ids[i] = t.ConditionID
+	}
+	ix, err := GetMarkets(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	return JoinTrades(trades, ix), nil
+}
+
+func (ix MarketIndex) lookup(conditionID string) *gamma.Market {
+	m, ok := ix[conditionID]
+	if !ok {
+		return nil
+	}
+	return &m
+}
@@ -0,0 +1,62 @@
+// gammago/data/profile.go
+
+package data
+
+import (
+	"context"
+	"net/url"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// ProfileStats are a wallet's totals from the Data API
+type ProfileStats struct {
+	// Value is the current value of the wallet's open positions
+	Value float64
+	// MarketsTraded is how many markets the wallet has traded
+	MarketsTraded int
+}
+
+// UserProfile is a Gamma profile with its Data API stats
+type UserProfile struct {
+	gamma.Profile
+	Stats ProfileStats
+}
+
+// GetProfileStats gets the stats of the wallet user
+func GetProfileStats(ctx context.Context, user string) (ProfileStats, error) {
+	params := url.Values{"user": {user}}
+
+	values, err := get[[]struct {
+		Value float64 `json:"value"`
+	}](ctx, "/value", params)
+	if err != nil {
+		return ProfileStats{}, err
+	}
+	traded, err := get[struct {
+		Traded int `json:"traded"`
+	}](ctx, "/traded", params)
+	if err != nil {
+		return ProfileStats{}, err
+	}
+
+	var stats ProfileStats
+	for _, v := range values {
+		stats.Value += v.Value
+	}
+	stats.MarketsTraded = traded.Traded
+	return stats, nil
+}
+
+// GetUserProfile gets the Gamma profile of address along with its stats
+func GetUserProfile(ctx context.Context, address string) (UserProfile, error) {
+	p, err := gamma.GetProfileByAddress(address)
+	if err != nil {
+		return UserProfile{}, err
+	}
+	stats, err := GetProfileStats(ctx, address)
+	if err != nil {
+		return UserProfile{}, err
+	}
+	return UserProfile{Profile: p, Stats: stats}, nil
+}
@@ -0,0 +1,401 @@
+// gammago/drift.go
+
+package gammago
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// DriftKind is the way a response differs from the Go types
+type DriftKind int
+
+const (
+	// UnknownField is a key the Go type doesn't declare
+	UnknownField DriftKind = iota
+	// TypeMismatch is a value whose JSON type can't decode into the field
+	TypeMismatch
+	// MissingField is a declared field absent from the response
+	MissingField
+)
+
+func (k DriftKind) String() string {
+	switch k {
+	case UnknownField:
+		return "unknown"
+	case TypeMismatch:
+		return "type"
+	case MissingField:
+		return "missing"
+	}
+	return fmt.Sprintf("DriftKind(%d)", int(k))
+}
+
+// Drift is one difference seen on an endpoint, aggregated over responses
+// Path is the JSON path, with [] for array elements, e.g. "[].events[].featured"
+// Count is how many objects showed the difference out of Objects seen at
+// that path
+type Drift struct {
+	Endpoint string
+	Path     string
+	Kind     DriftKind
+	JSONType string // the type in the response; empty for MissingField
+	GoType   string // the declared type; empty for UnknownField
+	Count    int
+	Objects  int
+}
+
+// DriftReport is every difference recorded since the last ResetDrift
+type DriftReport struct {
+	Drifts []Drift
+}
+
+// Empty reports whether no drift was recorded
+func (r DriftReport) Empty() bool {
+	return len(r.Drifts) == 0
+}
+
+// String renders the report as an aligned table
+func (r DriftReport) String() string {
+	if r.Empty() {
+		return "no drift\n"
+	}
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tKIND\tPATH\tDETAIL\tSEEN")
+	for _, d := range r.Drifts {
+		var detail string
+		switch d.Kind {
+		case UnknownField:
+			detail = d.JSONType
+		case TypeMismatch:
+			detail = fmt.Sprintf("got %s, want %s", d.JSONType, d.GoType)
+		case MissingField:
+			detail = d.GoType
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d/%d\n", d.Endpoint, d.Kind, d.Path, detail, d.Count, d.Objects)
+	}
+	tw.Flush()
+	return buf.String()
+}
+
+var (
+	strictMode bool
+	drift      = newDriftCollector()
+)
+
+// SetStrictMode turns schema drift detection on or off for every request
+// While on, each 2xx body is compared against the type it decodes into and
+// the differences are added to the drift report. Off by default, as it
+// decodes every body twice
+func SetStrictMode(on bool) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	strictMode = on
+}
+
+func getStrictMode() bool {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return strictMode
+}
+
+// GetDriftReport returns the drift recorded so far, sorted by endpoint,
+// kind and path
+func GetDriftReport() DriftReport {
+	return drift.report()
+}
+
+// ResetDrift clears the drift report
+func ResetDrift() {
+	drift.reset()
+}
+
+// CheckDrift compares a recorded response body against T, e.g. []Market,
+// and adds the differences to the drift report under endpoint
+// It works whether or not strict mode is on
+func CheckDrift[T any](endpoint string, body []byte) error {
+	_, err := drift.check(endpoint, reflect.TypeFor[T](), body)
+	return err
+}
+
+// DriftError is a strict mode decode failure, with the type mismatches
+// found in the same response. The mismatches are in the drift report too
+type DriftError struct {
+	Err        error
+	Mismatches []Drift
+}
+
+func (e *DriftError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error())
+	sb.WriteString(" (drift:")
+	for i, d := range e.Mismatches {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf(" %s got %s, want %s", cmp.Or(d.Path, "."), d.JSONType, d.GoType))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func (e *DriftError) Unwrap() error { return e.Err }
+
+// withDrift attaches mismatches to a decode error, if there are any
+func withDrift(err error, mismatches []Drift) error {
+	if len(mismatches) == 0 {
+		return err
+	}
+	return &DriftError{Err: err, Mismatches: mismatches}
+}
+
+type driftKey struct {
+	endpoint string
+	path     string
+	kind     DriftKind
+}
+
+type driftCollector struct {
+	mu      sync.Mutex
+	drifts  map[driftKey]*Drift
+	objects map[[2]string]int // endpoint, path -> objects seen
+	// mismatches are the type mismatches in the body being checked
+	mismatches []Drift
+}
+
+func newDriftCollector() *driftCollector {
+	return &driftCollector{drifts: map[driftKey]*Drift{}, objects: map[[2]string]int{}}
+}
+
+func (c *driftCollector) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.drifts = map[driftKey]*Drift{}
+	c.objects = map[[2]string]int{}
+}
+
+func (c *driftCollector) report() DriftReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := DriftReport{Drifts: make([]Drift, 0, len(c.drifts))}
+	for _, d := range c.drifts {
+		cp := *d
+		cp.Objects = c.objects[[2]string{d.Endpoint, parentPath(d.Path)}]
+		r.Drifts = append(r.Drifts, cp)
+	}
+	slices.SortFunc(r.Drifts, func(a, b Drift) int {
+		if n := strings.Compare(a.Endpoint, b.Endpoint); n != 0 {
+			return n
+		}
+		if a.Kind != b.Kind {
+			return int(a.Kind) - int(b.Kind)
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return r
+}
+
+// check records the drift of body against t and returns the type
+// mismatches in it, which explain a failure to decode body into t
+func (c *driftCollector) check(endpoint string, t reflect.Type, body []byte) ([]Drift, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mismatches = nil
+	c.walk(endpoint, "", t, v)
+	return c.mismatches, nil
+}
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	rawType  = reflect.TypeFor[json.RawMessage]()
+)
+
+// walk compares a generically decoded value against t; c.mu is held
+func (c *driftCollector) walk(endpoint, path string, t reflect.Type, v any) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if v == nil {
+		return // null decodes into anything
+	}
+
+	want := jsonTypeOf(t)
+	got := jsonTypeOfValue(v)
+	if want != "" && want != got {
+		c.add(endpoint, path, TypeMismatch, got, want)
+		return
+	}
+
+	switch v := v.(type) {
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, e := range v {
+				c.walk(endpoint, path+"[]", t.Elem(), e)
+			}
+		}
+	case map[string]any:
+		switch {
+		case t.Kind() == reflect.Map:
+			for k, e := range v {
+				c.walk(endpoint, joinPath(path, k), t.Elem(), e)
+			}
+		case t.Kind() == reflect.Struct && t != timeType:
+			c.walkStruct(endpoint, path, t, v)
+		}
+	}
+}
+
+func (c *driftCollector) walkStruct(endpoint, path string, t reflect.Type, obj map[string]any) {
+	c.objects[[2]string{endpoint, path}]++
+
+	fields := structFields(t)
+	present := make(map[string]bool, len(obj))
+	for k, e := range obj {
+		f, ok := fields[strings.ToLower(k)]
+		if !ok {
+			c.add(endpoint, joinPath(path, k), UnknownField, jsonTypeOfValue(e), "")
+			continue
+		}
+		present[strings.ToLower(k)] = true
+		c.walk(endpoint, joinPath(path, k), f.typ, e)
+	}
+	for key, f := range fields {
+		if !present[key] {
+			c.add(endpoint, joinPath(path, f.name), MissingField, "", goTypeName(f.typ))
+		}
+	}
+}
+
+func (c *driftCollector) add(endpoint, path string, kind DriftKind, jsonType, goType string) {
+	k := driftKey{endpoint, path, kind}
+	d, ok := c.drifts[k]
+	if !ok {
+		d = &Drift{Endpoint: endpoint, Path: path, Kind: kind, JSONType: jsonType, GoType: goType}
+		c.drifts[k] = d
+	}
+	d.Count++
+	if kind == TypeMismatch && !slices.ContainsFunc(c.mismatches, func(m Drift) bool { return m.Path == path }) {
+		c.mismatches = append(c.mismatches, Drift{Endpoint: endpoint, Path: path, Kind: kind, JSONType: jsonType, GoType: goType})
+	}
+}
+
+type structField struct {
+	name  string
+	typ   reflect.Type
+	index []int // for reflect.Value.FieldByIndex
+}
+
+// structFields maps the lower-cased JSON names of t's fields to the fields,
+// following embedded structs the way encoding/json does
+func structFields(t reflect.Type) map[string]structField {
+	fields := map[string]structField{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" {
+			et := f.Type
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				for k, sf := range structFields(et) {
+					if _, ok := fields[k]; !ok {
+						sf.index = append([]int{i}, sf.index...)
+						fields[k] = sf
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = structField{name: name, typ: f.Type, index: []int{i}}
+	}
+	return fields
+}
+
+// jsonTypeOf is the JSON type t decodes from, or "" if it takes anything
+func jsonTypeOf(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "string"
+	case t == rawType:
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // base64
+		}
+		return "array"
+	case reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
+
+func jsonTypeOfValue(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func goTypeName(t reflect.Type) string {
+	return strings.TrimPrefix(t.String(), "gammago.")
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// parentPath is the path of the object holding the field at path
+func parentPath(path string) string {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
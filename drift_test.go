@@ -0,0 +1,182 @@
+// gammago/drift_test.go
+
+package gammago
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func findDrift(r DriftReport, endpoint, path string, kind DriftKind) (Drift, bool) {
+	for _, d := range r.Drifts {
+		if d.Endpoint == endpoint && d.Path == path && d.Kind == kind {
+			return d, true
+		}
+	}
+	return Drift{}, false
+}
+
+func TestCheckDrift(t *testing.T) {
+	ResetDrift()
+	defer ResetDrift()
+
+	body := []byte(`[
+		{"id":"1","question":"A?","volume24hr":"12.5","cyom":true,"events":[{"id":"9","title":"E","openInterest":"n/a","seriesColor":"red"}],"tags":null},
+		{"id":"2","question":"B?","volume24hr":3,"cyom":false,"events":[]}
+	]`)
+	if err := CheckDrift[[]Market]("/markets", body); err != nil {
+		t.Fatalf("CheckDrift: %v", err)
+	}
+	r := GetDriftReport()
+
+	if d, ok := findDrift(r, "/markets", "[].cyom", UnknownField); !ok || d.JSONType != "bool" || d.Count != 2 || d.Objects != 2 {
+		t.Errorf("unknown cyom = %+v, %t", d, ok)
+	}
+	if d, ok := findDrift(r, "/markets", "[].volume24hr", TypeMismatch); !ok || d.JSONType != "string" || d.GoType != "number" || d.Count != 1 {
+		t.Errorf("volume24hr mismatch = %+v, %t", d, ok)
+	}
+	if d, ok := findDrift(r, "/markets", "[].events[].openInterest", TypeMismatch); !ok || d.Objects != 1 {
+		t.Errorf("nested mismatch = %+v, %t", d, ok)
+	}
+	if _, ok := findDrift(r, "/markets", "[].events[].seriesColor", UnknownField); !ok {
+		t.Error("nested unknown field not reported")
+	}
+	if d, ok := findDrift(r, "/markets", "[].closedTime", MissingField); !ok || d.GoType != "time.Time" || d.Count != 2 {
+		t.Errorf("missing closedTime = %+v, %t", d, ok)
+	}
+	if d, ok := findDrift(r, "/markets", "[].tags", MissingField); !ok || d.Count != 1 {
+		t.Errorf("null tags should count as present, got %+v", d)
+	}
+	for _, d := range r.Drifts {
+		if d.Path == "[].id" || d.Path == "[].Extra" {
+			t.Errorf("unexpected drift %+v", d)
+		}
+	}
+
+	out := r.String()
+	if !strings.HasPrefix(out, "ENDPOINT") || !strings.Contains(out, "got string, want number") {
+		t.Errorf("report:\n%s", out)
+	}
+
+	if err := CheckDrift[Market]("/markets", []byte(`{`)); err == nil {
+		t.Error("invalid JSON should fail")
+	}
+
+	ResetDrift()
+	if !GetDriftReport().Empty() || GetDriftReport().String() != "no drift\n" {
+		t.Error("ResetDrift left entries behind")
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+	ResetDrift()
+	defer ResetDrift()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"1","conditionId":"0xc1","rfqEnabled":true}]`))
+	}))
+	defer srv.Close()
+	SetBaseURL(srv.URL)
+	defer SetBaseURL("")
+
+	if _, err := GetMarketsByConditionIDs("0xc1"); err != nil {
+		t.Fatal(err)
+	}
+	if !GetDriftReport().Empty() {
+		t.Fatal("drift recorded with strict mode off")
+	}
+
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	if _, err := GetMarketsByConditionIDs("0xc1"); err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := findDrift(GetDriftReport(), "/markets", "[].rfqEnabled", UnknownField); !ok || d.Count != 1 {
+		t.Errorf("get: rfqEnabled = %+v, %t", d, ok)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := StreamMarketsBetweenDates(10, 0, start, start.Add(time.Hour), func(Market) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := findDrift(GetDriftReport(), "/markets", "[].rfqEnabled", UnknownField); !ok || d.Count != 2 || d.Objects != 2 {
+		t.Errorf("stream: rfqEnabled = %+v, %t", d, ok)
+	}
+}
+
+func TestStrictModeTypeChange(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+	ResetDrift()
+	defer ResetDrift()
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	body := `{"slug":5}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	SetBaseURL(srv.URL)
+	defer SetBaseURL("")
+
+	tests := []struct {
+		name, body, path, jsonType, goType string
+	}{
+		{"object for array", `{"slug":5}`, "", "object", "array"},
+		{"field type", `[{"id":"1","slug":5}]`, "[].slug", "number", "string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetDrift()
+			body = tt.body
+			_, err := GetTags(10, 0)
+
+			var de *DriftError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected a DriftError, got %v", err)
+			}
+			if len(de.Mismatches) != 1 || de.Mismatches[0].Path != tt.path || de.Mismatches[0].JSONType != tt.jsonType {
+				t.Errorf("unexpected mismatches %+v", de.Mismatches)
+			}
+			var te *json.UnmarshalTypeError
+			if !errors.As(err, &te) {
+				t.Errorf("decode error not wrapped: %v", err)
+			}
+			want := fmt.Sprintf("(drift: %s got %s, want %s)", cmp.Or(tt.path, "."), tt.jsonType, tt.goType)
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q doesn't contain %q", err, want)
+			}
+			if d, ok := findDrift(GetDriftReport(), "/tags", tt.path, TypeMismatch); !ok || d.GoType != tt.goType {
+				t.Errorf("report: %+v, %t", d, ok)
+			}
+		})
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		ResetDrift()
+		body = `[{"id":"1","question":"A?"},{"id":"2","question":["B?"]}]`
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		n := 0
+		err := StreamMarketsBetweenDates(10, 0, start, start.Add(time.Hour), func(Market) error { n++; return nil })
+
+		var de *DriftError
+		if !errors.As(err, &de) || n != 1 {
+			t.Fatalf("expected a DriftError after 1 market, got %d, %v", n, err)
+		}
+		if d, ok := findDrift(GetDriftReport(), "/markets", "[].question", TypeMismatch); !ok || d.JSONType != "array" {
+			t.Errorf("report: %+v, %t", d, ok)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+// gammago/eventtree.go
+
+package gammago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// errStopWalk ends a walk early once the consumer stops
+var errStopWalk = errors.New("stop walk")
+
+// EventNode is one event in an EventTree
+type EventNode struct {
+	Event    Event
+	Depth    int // 0 for the root
+	Parent   *EventNode
+	Children []*EventNode
+}
+
+// EventTree is an event with its parent events and sub-events resolved
+type EventTree struct {
+	Root   *EventNode // the topmost ancestor reached
+	Origin *EventNode // the event the tree was fetched for
+	// Truncated is set when the depth limit left parents or sub-events unfetched
+	Truncated bool
+}
+
+// GetEventTree fetches event id, follows ParentEvent up at most maxDepth
+// levels, then fetches sub-events down to maxDepth levels below id
+// Each event is fetched once, so cycles in the parent and sub-event links
+// end the walk instead of looping
+func GetEventTree(ctx context.Context, id string, maxDepth int) (*EventTree, error) {
+	if maxDepth < 0 {
+		return nil, fmt.Errorf("negative maxDepth %d", maxDepth)
+	}
+	b := treeBuilder{ctx: ctx, seen: map[string]bool{}}
+
+	origin, err := b.fetch(id)
+	if err != nil {
+		return nil, err
+	}
+	tree := &EventTree{}
+
+	// climb to the topmost ancestor, remembering the path down
+	path := []Event{origin}
+	for parentID := origin.ParentEvent; parentID != "" && !b.seen[parentID]; parentID = path[0].ParentEvent {
+		if len(path) > maxDepth {
+			tree.Truncated = true
+			break
+		}
+		parent, err := b.fetch(parentID)
+		if err != nil {
+			return nil, err
+		}
+		path = slices.Insert(path, 0, parent)
+	}
+
+	// rebuild the spine, keeping each ancestor's other sub-events
+	var node *EventNode
+	for depth, e := range path {
+		n := &EventNode{Event: e, Depth: depth, Parent: node}
+		if node == nil {
+			tree.Root = n
+		} else {
+			node.Children = append(node.Children, n)
+		}
+		node = n
+	}
+	tree.Origin = node
+
+	limit := len(path) - 1 + maxDepth
+	for n := tree.Root; n != nil; {
+		var next *EventNode
+		if n != tree.Origin {
+			next = n.Children[0]
+		}
+		if err := b.expand(tree, n, limit); err != nil {
+			return nil, err
+		}
+		n = next
+	}
+	return tree, nil
+}
+
+type treeBuilder struct {
+	ctx  context.Context
+	seen map[string]bool
+}
+
+func (b *treeBuilder) fetch(id string) (Event, error) {
+	reqUrl, _ := buildUrl(fmt.Sprintf("events/%s", id), nil)
+	e, err := genericGetCtx[Event](b.ctx, "/events/{id}", reqUrl)
+	if err != nil {
+		return Event{}, fmt.Errorf("event %s: %w", id, err)
+	}
+	b.seen[id] = true
+	return e, nil
+}
+
+// expand fetches the sub-events of n not already in the tree, recursing
+// while their depth is within limit
+func (b *treeBuilder) expand(tree *EventTree, n *EventNode, limit int) error {
+	for _, sub := range n.Event.SubEvents {
+		if sub.ID == "" || b.seen[sub.ID] {
+			continue
+		}
+		if n.Depth >= limit {
+			tree.Truncated = true
+			return nil
+		}
+		e, err := b.fetch(sub.ID)
+		if err != nil {
+			return err
+		}
+		child := &EventNode{Event: e, Depth: n.Depth + 1, Parent: n}
+		n.Children = append(n.Children, child)
+		if err := b.expand(tree, child, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for every event in the tree, parents before their children
+// Returning an error from fn stops the walk and returns that error
+func (t *EventTree) Walk(fn func(*EventNode) error) error {
+	var walk func(*EventNode) error
+	walk = func(n *EventNode) error {
+		if err := fn(n); err != nil {
+			return err
+		}
+		for _, c := range n.Children {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if t.Root == nil {
+		return nil
+	}
+	return walk(t.Root)
+}
+
+// Markets yields every market in the tree once, in walk order
+func (t *EventTree) Markets() iter.Seq[Market] {
+	return func(yield func(Market) bool) {
+		seen := map[string]bool{}
+		t.Walk(func(n *EventNode) error {
+			for _, m := range n.Event.Markets {
+				if seen[m.ID] {
+					continue
+				}
+				seen[m.ID] = true
+				if !yield(m) {
+					return errStopWalk
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// Flatten returns every market in the tree once, in walk order
+func (t *EventTree) Flatten() []Market {
+	return slices.Collect(t.Markets())
+}
@@ -0,0 +1,150 @@
+// gammago/eventtree_test.go
+
+package gammago
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// event tree used below:
+//
+//	top (m1)
+//	├── mid (m2, m3)
+//	│   ├── leaf (m3, m4)
+//	│   │   └── deep (m5)
+//	│   └── loop -> sub-event mid again
+//	└── other (m6)
+var treeEvents = map[string]string{
+	"top":   `{"id":"top","subEvents":[{"id":"mid"},{"id":"other"}],"markets":[{"id":"m1"}]}`,
+	"mid":   `{"id":"mid","parentEvent":"top","subEvents":[{"id":"leaf"},{"id":"loop"}],"markets":[{"id":"m2"},{"id":"m3"}]}`,
+	"leaf":  `{"id":"leaf","parentEvent":"mid","subEvents":[{"id":"deep"}],"markets":[{"id":"m3"},{"id":"m4"}]}`,
+	"deep":  `{"id":"deep","parentEvent":"leaf","markets":[{"id":"m5"}]}`,
+	"loop":  `{"id":"loop","parentEvent":"mid","subEvents":[{"id":"mid"}]}`,
+	"other": `{"id":"other","parentEvent":"top","markets":[{"id":"m6"}]}`,
+	"self":  `{"id":"self","parentEvent":"self","subEvents":[{"id":"self"}],"markets":[{"id":"m7"}]}`,
+}
+
+func treeServer(t *testing.T) *[]string {
+	var mu sync.Mutex
+	var fetched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/events/")
+		body, ok := treeEvents[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		fetched = append(fetched, id)
+		mu.Unlock()
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	SetBaseURL(srv.URL)
+	t.Cleanup(func() { SetBaseURL("") })
+	return &fetched
+}
+
+func treeIDs(tree *EventTree) string {
+	var ids []string
+	tree.Walk(func(n *EventNode) error {
+		ids = append(ids, strings.Repeat(" ", n.Depth)+n.Event.ID)
+		return nil
+	})
+	return strings.Join(ids, ",")
+}
+
+func marketIDs(markets []Market) string {
+	var ids []string
+	for _, m := range markets {
+		ids = append(ids, m.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestGetEventTree(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	t.Run("from the middle", func(t *testing.T) {
+		fetched := treeServer(t)
+		tree, err := GetEventTree(t.Context(), "mid", 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tree.Root.Event.ID != "top" || tree.Origin.Event.ID != "mid" || tree.Origin.Parent != tree.Root || tree.Truncated {
+			t.Errorf("root %s origin %s truncated %t", tree.Root.Event.ID, tree.Origin.Event.ID, tree.Truncated)
+		}
+		if got := treeIDs(tree); got != "top, mid,  leaf,   deep,  loop, other" {
+			t.Errorf("tree = %q", got)
+		}
+		if got := marketIDs(tree.Flatten()); got != "m1,m2,m3,m4,m5,m6" {
+			t.Errorf("markets = %q", got)
+		}
+		if len(*fetched) != 6 {
+			t.Errorf("each event should be fetched once, got %v", *fetched)
+		}
+	})
+
+	t.Run("depth limit", func(t *testing.T) {
+		treeServer(t)
+		tree, err := GetEventTree(t.Context(), "leaf", 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !tree.Truncated || tree.Root.Event.ID != "mid" {
+			t.Errorf("root %s truncated %t", tree.Root.Event.ID, tree.Truncated)
+		}
+		if got := treeIDs(tree); got != "mid, leaf,  deep, loop" {
+			t.Errorf("tree = %q", got)
+		}
+
+		tree, err = GetEventTree(t.Context(), "leaf", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := treeIDs(tree); got != "leaf" || !tree.Truncated {
+			t.Errorf("tree = %q, truncated %t", got, tree.Truncated)
+		}
+	})
+
+	t.Run("self reference", func(t *testing.T) {
+		treeServer(t)
+		tree, err := GetEventTree(t.Context(), "self", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := treeIDs(tree); got != "self" || tree.Truncated {
+			t.Errorf("tree = %q, truncated %t", got, tree.Truncated)
+		}
+	})
+
+	t.Run("early stop", func(t *testing.T) {
+		treeServer(t)
+		tree, _ := GetEventTree(t.Context(), "top", 5)
+		var got []string
+		for m := range tree.Markets() {
+			got = append(got, m.ID)
+			if len(got) == 2 {
+				break
+			}
+		}
+		if strings.Join(got, ",") != "m1,m2" {
+			t.Errorf("markets = %v", got)
+		}
+	})
+
+	t.Run("missing event", func(t *testing.T) {
+		treeServer(t)
+		if _, err := GetEventTree(t.Context(), "nope", 5); err == nil || !strings.Contains(err.Error(), "event nope") {
+			t.Errorf("expected an error naming the event, got %v", err)
+		}
+		if _, err := GetEventTree(t.Context(), "top", -1); err == nil {
+			t.Error("negative depth should fail")
+		}
+	})
+}
@@ -0,0 +1,150 @@
+// gammago/export/columns.go
+
+package export
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// ListSeparator joins flattened list values (outcomes, prices, tags) inside one cell
+const ListSeparator = "|"
+
+// DefaultMarketColumns is used when NewMarketCSV is given no column names
+var DefaultMarketColumns = []string{
+	"id", "question", "slug", "active", "start_date", "end_date",
+	"outcomes", "outcome_prices", "volume", "volume_24hr", "liquidity", "spread", "tags",
+}
+
+// DefaultEventColumns is used when NewEventCSV is given no column names
+var DefaultEventColumns = []string{
+	"id", "title", "slug", "active", "start_date", "end_date",
+	"volume", "volume_24hr", "liquidity", "neg_risk", "market_count", "tags",
+}
+
+// MarketColumns are the columns available for markets
+var MarketColumns = map[string]func(gamma.Market) string{
+	"id":                 func(m gamma.Market) string { return m.ID },
+	"question":           func(m gamma.Market) string { return m.Question },
+	"condition_id":       func(m gamma.Market) string { return m.ConditionID },
+	"slug":               func(m gamma.Market) string { return m.Slug },
+	"active":             func(m gamma.Market) string { return strconv.FormatBool(m.Active) },
+	"category":           func(m gamma.Market) string { return m.Category },
+	"market_type":        func(m gamma.Market) string { return m.MarketType },
+	"sports_market_type": func(m gamma.Market) string { return m.SportsMarketType },
+	"start_date":         func(m gamma.Market) string { return formatTime(m.StartDate) },
+	"end_date":           func(m gamma.Market) string { return formatTime(m.EndDate) },
+	"outcomes":           func(m gamma.Market) string { return joinEncoded(m.Outcomes) },
+	"outcome_prices":     func(m gamma.Market) string { return joinEncoded(m.OutcomePrices) },
+	"yes_price":          func(m gamma.Market) string { return outcomePrice(m, "Yes") },
+	"no_price":           func(m gamma.Market) string { return outcomePrice(m, "No") },
+	"clob_token_ids":     func(m gamma.Market) string { return joinEncoded(m.CLOBTokenIDs) },
+	"volume":             func(m gamma.Market) string { return m.Volume },
+	"volume_24hr":        func(m gamma.Market) string { return formatFloat(m.Volume24hr) },
+	"volume_1wk":         func(m gamma.Market) string { return formatFloat(m.Volume1wk) },
+	"volume_1mo":         func(m gamma.Market) string { return formatFloat(m.Volume1mo) },
+	"liquidity":          func(m gamma.Market) string { return m.Liquidity },
+	"spread":             func(m gamma.Market) string { return formatFloat(m.Spread) },
+	"line":               func(m gamma.Market) string { return formatFloat(m.Line) },
+	"event_id": func(m gamma.Market) string {
+		if len(m.Events) == 0 {
+			return ""
+		}
+		return m.Events[0].ID
+	},
+	"tags":       func(m gamma.Market) string { return strings.Join(TagLabels(m.Tags), ListSeparator) },
+	"categories": func(m gamma.Market) string { return strings.Join(CategoryLabels(m.Categories), ListSeparator) },
+}
+
+// EventColumns are the columns available for events
+var EventColumns = map[string]func(gamma.Event) string{
+	"id":           func(e gamma.Event) string { return e.ID },
+	"ticker":       func(e gamma.Event) string { return e.Ticker },
+	"slug":         func(e gamma.Event) string { return e.Slug },
+	"title":        func(e gamma.Event) string { return e.Title },
+	"active":       func(e gamma.Event) string { return strconv.FormatBool(e.Active) },
+	"category":     func(e gamma.Event) string { return e.Category },
+	"subcategory":  func(e gamma.Event) string { return e.Subcategory },
+	"start_date":   func(e gamma.Event) string { return formatTime(e.StartDate) },
+	"end_date":     func(e gamma.Event) string { return formatTime(e.EndDate) },
+	"volume":       func(e gamma.Event) string { return formatFloat(e.Volume) },
+	"volume_24hr":  func(e gamma.Event) string { return formatFloat(e.Volume24hr) },
+	"liquidity":    func(e gamma.Event) string { return formatFloat(e.Liquidity) },
+	"neg_risk":     func(e gamma.Event) string { return strconv.FormatBool(e.NegRisk) },
+	"market_count": func(e gamma.Event) string { return strconv.Itoa(len(e.Markets)) },
+	"market_ids": func(e gamma.Event) string {
+		ids := make([]string, len(e.Markets))
+		for i, m := range e.Markets {
+			ids[i] = m.ID
+		}
+		return strings.Join(ids, ListSeparator)
+	},
+	"series": func(e gamma.Event) string {
+		titles := make([]string, len(e.Series))
+		for i, s := range e.Series {
+			titles[i] = s.Title
+		}
+		return strings.Join(titles, ListSeparator)
+	},
+	"tags":       func(e gamma.Event) string { return strings.Join(TagLabels(e.Tags), ListSeparator) },
+	"categories": func(e gamma.Event) string { return strings.Join(CategoryLabels(e.Categories), ListSeparator) },
+}
+
+// DecodeList parses a JSON-encoded string list like `["Yes", "No"]`
+// Gamma sends outcomes, prices and token IDs this way
+func DecodeList(s string) []string {
+	var list []string
+	if err := json.Unmarshal([]byte(s), &list); err != nil {
+		return nil
+	}
+	return list
+}
+
+func joinEncoded(s string) string {
+	return strings.Join(DecodeList(s), ListSeparator)
+}
+
+// outcomePrice is the price of the outcome with the given label, or "" if absent
+func outcomePrice(m gamma.Market, label string) string {
+	outcomes := DecodeList(m.Outcomes)
+	prices := DecodeList(m.OutcomePrices)
+	for i, o := range outcomes {
+		if strings.EqualFold(o, label) && i < len(prices) {
+			return prices[i]
+		}
+	}
+	return ""
+}
+
+// TagLabels lists the labels of tags
+func TagLabels(tags []gamma.Tag) []string {
+	labels := make([]string, len(tags))
+	for i, t := range tags {
+		labels[i] = t.Label
+	}
+	return labels
+}
+
+// CategoryLabels lists the labels of cats
+func CategoryLabels(cats []gamma.Category) []string {
+	labels := make([]string, len(cats))
+	for i, c := range cats {
+		labels[i] = c.Label
+	}
+	return labels
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
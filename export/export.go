@@ -0,0 +1,160 @@
+// gammago/export/export.go
+
+// Package export writes events and markets as CSV or NDJSON
+// Records are written one at a time, so it can be fed straight from
+// the Stream functions without buffering a whole page
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// Writer writes records of type T
+// Call Flush once all records are written
+type Writer[T any] interface {
+	Write(v T) error
+	Flush() error
+}
+
+// RowError is a record a Writer rejected without writing any of it
+// The writer stays usable, so callers can skip the record and go on
+type RowError struct {
+	Err error
+}
+
+func (e *RowError) Error() string { return e.Err.Error() }
+func (e *RowError) Unwrap() error { return e.Err }
+
+// Column is a named CSV column
+type Column[T any] struct {
+	Name  string
+	Value func(T) string
+}
+
+// CSVWriter writes one CSV row per record, with a header row first
+type CSVWriter[T any] struct {
+	w             *csv.Writer
+	columns       []Column[T]
+	headerWritten bool
+}
+
+// NewCSV returns a CSVWriter with the given columns
+func NewCSV[T any](w io.Writer, columns []Column[T]) *CSVWriter[T] {
+	return &CSVWriter[T]{w: csv.NewWriter(w), columns: columns}
+}
+
+// NewMarketCSV returns a CSVWriter for markets
+// names pick columns from MarketColumns; none means DefaultMarketColumns
+func NewMarketCSV(w io.Writer, names ...string) (*CSVWriter[gamma.Market], error) {
+	if len(names) == 0 {
+		names = DefaultMarketColumns
+	}
+	cols, err := selectColumns(MarketColumns, names)
+	if err != nil {
+		return nil, err
+	}
+	return NewCSV(w, cols), nil
+}
+
+// NewEventCSV returns a CSVWriter for events
+// names pick columns from EventColumns; none means DefaultEventColumns
+func NewEventCSV(w io.Writer, names ...string) (*CSVWriter[gamma.Event], error) {
+	if len(names) == 0 {
+		names = DefaultEventColumns
+	}
+	cols, err := selectColumns(EventColumns, names)
+	if err != nil {
+		return nil, err
+	}
+	return NewCSV(w, cols), nil
+}
+
+// Write writes the header on first use, then one row
+func (c *CSVWriter[T]) Write(v T) error {
+	if !c.headerWritten {
+		header := make([]string, len(c.columns))
+		for i, col := range c.columns {
+			header[i] = col.Name
+		}
+		if err := c.w.Write(header); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+
+	row := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		row[i] = col.Value(v)
+	}
+	return c.w.Write(row)
+}
+
+// Flush writes any buffered rows
+func (c *CSVWriter[T]) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// NDJSONWriter writes one JSON object per line
+type NDJSONWriter[T any] struct {
+	enc *json.Encoder
+}
+
+// NewNDJSON returns an NDJSONWriter
+func NewNDJSON[T any](w io.Writer) *NDJSONWriter[T] {
+	return &NDJSONWriter[T]{enc: json.NewEncoder(w)}
+}
+
+// Write writes v followed by a newline
+func (n *NDJSONWriter[T]) Write(v T) error {
+	return n.enc.Encode(v)
+}
+
+// Flush is a no-op; every Write goes straight to the underlying writer
+func (n *NDJSONWriter[T]) Flush() error {
+	return nil
+}
+
+// WriteAll writes every record then flushes
+func WriteAll[T any](w Writer[T], records []T) error {
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// WriteSeq writes every record from seq then flushes
+func WriteSeq[T any](w Writer[T], seq iter.Seq[T]) error {
+	for r := range seq {
+		if err := w.Write(r); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func selectColumns[T any](available map[string]func(T) string, names []string) ([]Column[T], error) {
+	cols := make([]Column[T], 0, len(names))
+	for _, name := range names {
+		fn, ok := available[name]
+		if !ok {
+			known := make([]string, 0, len(available))
+			for k := range available {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown column %q (available: %v)", name, known)
+		}
+		cols = append(cols, Column[T]{Name: name, Value: fn})
+	}
+	return cols, nil
+}
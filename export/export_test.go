@@ -0,0 +1,105 @@
+// gammago/export/export_test.go
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+var testMarkets = []gamma.Market{
+	{
+		ID:            "501",
+		Question:      "Will Lakers win?",
+		Slug:          "lakers-win",
+		Active:        true,
+		EndDate:       time.Date(2026, 1, 15, 22, 0, 0, 0, time.UTC),
+		Outcomes:      `["Yes", "No"]`,
+		OutcomePrices: `["0.45", "0.55"]`,
+		Volume24hr:    1234.5,
+		Tags:          []gamma.Tag{{Label: "Sports"}, {Label: "NBA"}},
+	},
+	{
+		ID:       "502",
+		Question: "Total points, over 220.5?",
+		Outcomes: `not json`,
+	},
+}
+
+func TestCSV(t *testing.T) {
+	t.Run("selected market columns with flattened lists", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := NewMarketCSV(&buf, "id", "question", "end_date", "outcomes", "outcome_prices", "yes_price", "tags")
+		if err != nil {
+			t.Fatalf("NewMarketCSV failed: %v", err)
+		}
+		if err := WriteAll(w, testMarkets); err != nil {
+			t.Fatalf("WriteAll failed: %v", err)
+		}
+
+		want := "id,question,end_date,outcomes,outcome_prices,yes_price,tags\n" +
+			"501,Will Lakers win?,2026-01-15T22:00:00Z,Yes|No,0.45|0.55,0.45,Sports|NBA\n" +
+			"502,\"Total points, over 220.5?\",,,,,\n"
+		if buf.String() != want {
+			t.Errorf("csv mismatch\nwant: %q\ngot:  %q", want, buf.String())
+		}
+	})
+
+	t.Run("default event columns", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := NewEventCSV(&buf)
+		if err != nil {
+			t.Fatalf("NewEventCSV failed: %v", err)
+		}
+		events := []gamma.Event{{ID: "1", Title: "Lakers vs Celtics", NegRisk: true, Markets: testMarkets}}
+		if err := WriteAll(w, events); err != nil {
+			t.Fatalf("WriteAll failed: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if lines[0] != strings.Join(DefaultEventColumns, ",") {
+			t.Errorf("header = %q", lines[0])
+		}
+		if !strings.HasPrefix(lines[1], "1,Lakers vs Celtics,") || !strings.Contains(lines[1], ",true,2,") {
+			t.Errorf("row = %q", lines[1])
+		}
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		if _, err := NewMarketCSV(&bytes.Buffer{}, "id", "nope"); err == nil {
+			t.Error("expected error for unknown column")
+		}
+	})
+
+	t.Run("no records writes nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, _ := NewMarketCSV(&buf)
+		WriteAll(w, nil)
+		if buf.Len() != 0 {
+			t.Errorf("expected empty output, got %q", buf.String())
+		}
+	})
+}
+
+func TestNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewNDJSON[gamma.Market](&buf)
+	if err := WriteSeq(w, slices.Values(testMarkets)); err != nil {
+		t.Fatalf("WriteSeq failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var m gamma.Market
+	if err := json.Unmarshal([]byte(lines[0]), &m); err != nil || m.ID != "501" {
+		t.Errorf("line 0 = %q, err = %v", lines[0], err)
+	}
+}
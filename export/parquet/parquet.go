@@ -0,0 +1,435 @@
+// gammago/export/parquet/parquet.go
+
+// Package parquet writes markets and events as Apache Parquet files
+// It is pure Go with no dependencies: values are PLAIN encoded, levels are
+// RLE encoded, and pages are either uncompressed or gzip compressed
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/Bazcampbell/gammago/export"
+)
+
+// Codec is the page compression codec
+type Codec int32
+
+const (
+	Uncompressed Codec = 0
+	Gzip         Codec = 2
+)
+
+// DefaultRowGroupSize is used when Options.RowGroupSize is 0
+const DefaultRowGroupSize = 10000
+
+// Options configures a Writer
+type Options struct {
+	// RowGroupSize is the number of rows buffered per row group
+	RowGroupSize int
+	Codec        Codec
+}
+
+var magic = []byte("PAR1")
+
+// Parquet physical types
+const (
+	typeBoolean   int32 = 0
+	typeInt64     int32 = 2
+	typeDouble    int32 = 5
+	typeByteArray int32 = 6
+)
+
+// Field repetition types
+const (
+	required int32 = 0
+	optional int32 = 1
+	repeated int32 = 2
+)
+
+// Converted (logical) types, -1 for none
+const (
+	convNone            int32 = -1
+	convUTF8            int32 = 0
+	convDecimal         int32 = 5
+	convTimestampMillis int32 = 9
+)
+
+// Encodings
+const (
+	encodingPlain int32 = 0
+	encodingRLE   int32 = 3
+)
+
+const pageTypeData int32 = 0
+
+// column is one leaf column and how to pull its values out of a record
+// values returns exactly one value for required columns, at most one
+// for optional columns and any number for repeated columns
+// Values are bool, int64, float64 or string to match typ
+// An error rejects the whole row
+type column[T any] struct {
+	name       string
+	typ        int32
+	repetition int32
+	converted  int32
+	values     func(T) ([]any, error)
+}
+
+// columnBuffer holds one column of the current row group
+type columnBuffer struct {
+	def, rep []int32
+	values   bytes.Buffer
+	bools    []bool
+	levels   int // number of level entries, i.e. num_values in the page header
+}
+
+type chunkMeta struct {
+	offset       int64
+	numValues    int64
+	uncompressed int64
+	compressed   int64
+}
+
+type rowGroup struct {
+	chunks  []chunkMeta
+	numRows int64
+	size    int64
+}
+
+// Writer writes records of type T to a Parquet file
+// Rows are buffered per row group; Close writes the footer
+type Writer[T any] struct {
+	w         io.Writer
+	offset    int64
+	opts      Options
+	columns   []column[T]
+	buffers   []*columnBuffer
+	rows      int
+	totalRows int64
+	groups    []rowGroup
+	closed    bool
+}
+
+func newWriter[T any](w io.Writer, columns []column[T], opts Options) *Writer[T] {
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = DefaultRowGroupSize
+	}
+	pw := &Writer[T]{w: w, opts: opts, columns: columns}
+	pw.resetBuffers()
+	return pw
+}
+
+func (pw *Writer[T]) resetBuffers() {
+	pw.buffers = make([]*columnBuffer, len(pw.columns))
+	for i := range pw.buffers {
+		pw.buffers[i] = &columnBuffer{}
+	}
+	pw.rows = 0
+}
+
+// Write adds one row, flushing a row group when it is full
+func (pw *Writer[T]) Write(v T) error {
+	if pw.closed {
+		return errors.New("parquet: write after close")
+	}
+
+	// pull every value before buffering any, so a rejected row leaves
+	// the columns aligned
+	row := make([][]any, len(pw.columns))
+	for i, c := range pw.columns {
+		vals, err := c.values(v)
+		if err != nil {
+			return &export.RowError{Err: fmt.Errorf("parquet: column %s: %w", c.name, err)}
+		}
+		row[i] = vals
+	}
+	for i, c := range pw.columns {
+		if err := pw.buffers[i].add(c.typ, c.repetition, row[i]); err != nil {
+			return fmt.Errorf("parquet: column %s: %w", c.name, err)
+		}
+	}
+	pw.rows++
+
+	if pw.rows >= pw.opts.RowGroupSize {
+		return pw.flushRowGroup()
+	}
+	return nil
+}
+
+// Flush writes the buffered rows and the footer, completing the file
+// It makes Writer satisfy export.Writer; the writer can't be used afterwards
+func (pw *Writer[T]) Flush() error {
+	return pw.Close()
+}
+
+// Close writes the buffered rows and the footer, completing the file
+func (pw *Writer[T]) Close() error {
+	if pw.closed {
+		return nil
+	}
+	pw.closed = true
+
+	if err := pw.writeMagic(); err != nil {
+		return err
+	}
+	if pw.rows > 0 {
+		if err := pw.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	footer := pw.fileMetaData()
+	if err := pw.write(footer); err != nil {
+		return err
+	}
+	if err := pw.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return pw.write(magic)
+}
+
+func (pw *Writer[T]) write(p []byte) error {
+	n, err := pw.w.Write(p)
+	pw.offset += int64(n)
+	return err
+}
+
+func (pw *Writer[T]) writeMagic() error {
+	if pw.offset > 0 {
+		return nil
+	}
+	return pw.write(magic)
+}
+
+func (pw *Writer[T]) flushRowGroup() error {
+	if err := pw.writeMagic(); err != nil {
+		return err
+	}
+
+	group := rowGroup{numRows: int64(pw.rows)}
+	for i, c := range pw.columns {
+		chunk, err := pw.writeChunk(c, pw.buffers[i])
+		if err != nil {
+			return fmt.Errorf("parquet: column %s: %w", c.name, err)
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.uncompressed
+	}
+
+	pw.groups = append(pw.groups, group)
+	pw.totalRows += int64(pw.rows)
+	pw.resetBuffers()
+	return nil
+}
+
+// writeChunk writes a column chunk as a single data page
+func (pw *Writer[T]) writeChunk(c column[T], b *columnBuffer) (chunkMeta, error) {
+	var body bytes.Buffer
+	if c.repetition == repeated {
+		writeLevels(&body, b.rep)
+	}
+	if c.repetition != required {
+		writeLevels(&body, b.def)
+	}
+	if c.typ == typeBoolean {
+		body.Write(packBools(b.bools))
+	}
+	body.Write(b.values.Bytes())
+
+	page := body.Bytes()
+	if pw.opts.Codec == Gzip {
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		if _, err := zw.Write(page); err != nil {
+			return chunkMeta{}, err
+		}
+		if err := zw.Close(); err != nil {
+			return chunkMeta{}, err
+		}
+		page = gz.Bytes()
+	}
+
+	var h thriftWriter
+	h.structBegin()
+	h.fieldI32(1, pageTypeData)
+	h.fieldI32(2, int32(body.Len()))
+	h.fieldI32(3, int32(len(page)))
+	h.fieldStruct(5)
+	h.fieldI32(1, int32(b.levels))
+	h.fieldI32(2, encodingPlain)
+	h.fieldI32(3, encodingRLE)
+	h.fieldI32(4, encodingRLE)
+	h.structEnd()
+	h.structEnd()
+
+	chunk := chunkMeta{
+		offset:       pw.offset,
+		numValues:    int64(b.levels),
+		uncompressed: int64(len(h.bytes()) + body.Len()),
+		compressed:   int64(len(h.bytes()) + len(page)),
+	}
+	if err := pw.write(h.bytes()); err != nil {
+		return chunk, err
+	}
+	return chunk, pw.write(page)
+}
+
+func (pw *Writer[T]) fileMetaData() []byte {
+	var t thriftWriter
+	t.structBegin()
+	t.fieldI32(1, 1) // version
+
+	t.fieldList(2, thriftStruct, len(pw.columns)+1)
+	t.structBegin() // root
+	t.fieldString(4, "schema")
+	t.fieldI32(5, int32(len(pw.columns)))
+	t.structEnd()
+	for _, c := range pw.columns {
+		t.structBegin()
+		t.fieldI32(1, c.typ)
+		t.fieldI32(3, c.repetition)
+		t.fieldString(4, c.name)
+		if c.converted != convNone {
+			t.fieldI32(6, c.converted)
+		}
+		if c.converted == convDecimal {
+			t.fieldI32(7, DecimalScale)
+			t.fieldI32(8, DecimalPrecision)
+		}
+		t.structEnd()
+	}
+
+	t.fieldI64(3, pw.totalRows)
+
+	t.fieldList(4, thriftStruct, len(pw.groups))
+	for _, g := range pw.groups {
+		t.structBegin()
+		t.fieldList(1, thriftStruct, len(g.chunks))
+		for i, ch := range g.chunks {
+			c := pw.columns[i]
+			t.structBegin()
+			t.fieldI64(2, ch.offset)
+			t.fieldStruct(3)
+			t.fieldI32(1, c.typ)
+			t.fieldList(2, thriftI32, 2)
+			t.listI32(encodingPlain)
+			t.listI32(encodingRLE)
+			t.fieldList(3, thriftBinary, 1)
+			t.listString(c.name)
+			t.fieldI32(4, int32(pw.opts.Codec))
+			t.fieldI64(5, ch.numValues)
+			t.fieldI64(6, ch.uncompressed)
+			t.fieldI64(7, ch.compressed)
+			t.fieldI64(9, ch.offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.fieldI64(2, g.size)
+		t.fieldI64(3, g.numRows)
+		t.structEnd()
+	}
+
+	t.fieldString(6, "gammago")
+	t.structEnd()
+	return t.bytes()
+}
+
+// add appends one record's values and levels
+func (b *columnBuffer) add(typ, repetition int32, vals []any) error {
+	switch repetition {
+	case required:
+		if len(vals) != 1 {
+			return fmt.Errorf("required column got %d values", len(vals))
+		}
+		b.levels++
+		return b.appendValue(typ, vals[0])
+
+	case optional:
+		b.levels++
+		if len(vals) == 0 {
+			b.def = append(b.def, 0)
+			return nil
+		}
+		b.def = append(b.def, 1)
+		return b.appendValue(typ, vals[0])
+
+	case repeated:
+		if len(vals) == 0 {
+			b.levels++
+			b.rep = append(b.rep, 0)
+			b.def = append(b.def, 0)
+			return nil
+		}
+		for i, v := range vals {
+			b.levels++
+			b.rep = append(b.rep, min(int32(i), 1))
+			b.def = append(b.def, 1)
+			if err := b.appendValue(typ, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendValue PLAIN encodes one value
+func (b *columnBuffer) appendValue(typ int32, v any) error {
+	switch typ {
+	case typeBoolean:
+		if x, ok := v.(bool); ok {
+			b.bools = append(b.bools, x)
+			return nil
+		}
+	case typeInt64:
+		if x, ok := v.(int64); ok {
+			b.values.Write(binary.LittleEndian.AppendUint64(nil, uint64(x)))
+			return nil
+		}
+	case typeDouble:
+		if x, ok := v.(float64); ok {
+			b.values.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(x)))
+			return nil
+		}
+	case typeByteArray:
+		if x, ok := v.(string); ok {
+			b.values.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(x))))
+			b.values.WriteString(x)
+			return nil
+		}
+	}
+	return fmt.Errorf("value %T does not match physical type %d", v, typ)
+}
+
+// writeLevels writes bit width 1 levels as a length-prefixed
+// RLE/bit-packed hybrid, using only RLE runs
+func writeLevels(w *bytes.Buffer, levels []int32) {
+	var runs []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		runs = binary.AppendUvarint(runs, uint64(j-i)<<1)
+		runs = append(runs, byte(levels[i]))
+		i = j
+	}
+	w.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(runs))))
+	w.Write(runs)
+}
+
+// packBools PLAIN encodes booleans, one bit each, least significant bit first
+func packBools(bools []bool) []byte {
+	out := make([]byte, (len(bools)+7)/8)
+	for i, v := range bools {
+		if v {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
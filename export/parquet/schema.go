@@ -0,0 +1,222 @@
+// gammago/export/parquet/schema.go
+
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+	"github.com/Bazcampbell/gammago/export"
+)
+
+// Decimal columns are INT64 DECIMAL(18, 6)
+const (
+	DecimalScale     = 6
+	DecimalPrecision = 18
+)
+
+// NewMarketWriter returns a Writer with the market schema:
+//
+//	id, question, condition_id, slug, category, market_type,
+//	sports_market_type                          required UTF8
+//	active                                      required BOOLEAN
+//	start_date, end_date, event_start_time      optional TIMESTAMP_MILLIS
+//	volume, liquidity                           optional DECIMAL(18,6)
+//	volume_24hr, volume_1wk, volume_1mo,
+//	volume_1yr, spread, line                    required DOUBLE
+//	event_id                                    optional UTF8
+//	outcomes, tags, categories, clob_token_ids  repeated UTF8
+//	outcome_prices                              repeated DECIMAL(18,6)
+//
+// Columns are only ever appended, so existing readers keep working
+func NewMarketWriter(w io.Writer, opts Options) *Writer[gamma.Market] {
+	return newWriter(w, marketColumns, opts)
+}
+
+// NewEventWriter returns a Writer with the event schema:
+//
+//	id, ticker, slug, title, category, subcategory  required UTF8
+//	active, neg_risk                                required BOOLEAN
+//	start_date, end_date, created_at, updated_at    optional TIMESTAMP_MILLIS
+//	volume, volume_24hr, liquidity                  required DOUBLE
+//	neg_risk_market_id                              optional UTF8
+//	market_ids, tags, categories, series            repeated UTF8
+func NewEventWriter(w io.Writer, opts Options) *Writer[gamma.Event] {
+	return newWriter(w, eventColumns, opts)
+}
+
+var marketColumns = []column[gamma.Market]{
+	utf8Column("id", func(m gamma.Market) string { return m.ID }),
+	utf8Column("question", func(m gamma.Market) string { return m.Question }),
+	utf8Column("condition_id", func(m gamma.Market) string { return m.ConditionID }),
+	utf8Column("slug", func(m gamma.Market) string { return m.Slug }),
+	utf8Column("category", func(m gamma.Market) string { return m.Category }),
+	utf8Column("market_type", func(m gamma.Market) string { return m.MarketType }),
+	utf8Column("sports_market_type", func(m gamma.Market) string { return m.SportsMarketType }),
+	boolColumn("active", func(m gamma.Market) bool { return m.Active }),
+	timestampColumn("start_date", func(m gamma.Market) time.Time { return m.StartDate }),
+	timestampColumn("end_date", func(m gamma.Market) time.Time { return m.EndDate }),
+	timestampColumn("event_start_time", func(m gamma.Market) time.Time { return m.EventStartTime }),
+	decimalColumn("volume", func(m gamma.Market) string { return m.Volume }),
+	decimalColumn("liquidity", func(m gamma.Market) string { return m.Liquidity }),
+	doubleColumn("volume_24hr", func(m gamma.Market) float64 { return m.Volume24hr }),
+	doubleColumn("volume_1wk", func(m gamma.Market) float64 { return m.Volume1wk }),
+	doubleColumn("volume_1mo", func(m gamma.Market) float64 { return m.Volume1mo }),
+	doubleColumn("volume_1yr", func(m gamma.Market) float64 { return m.Volume1yr }),
+	doubleColumn("spread", func(m gamma.Market) float64 { return m.Spread }),
+	doubleColumn("line", func(m gamma.Market) float64 { return m.Line }),
+	optionalUTF8Column("event_id", func(m gamma.Market) string {
+		if len(m.Events) == 0 {
+			return ""
+		}
+		return m.Events[0].ID
+	}),
+	repeatedUTF8Column("outcomes", func(m gamma.Market) []string { return export.DecodeList(m.Outcomes) }),
+	{
+		name:       "outcome_prices",
+		typ:        typeInt64,
+		repetition: repeated,
+		converted:  convDecimal,
+		// a price that doesn't parse fails the row rather than shifting the
+		// rest out of line with outcomes
+		values: func(m gamma.Market) ([]any, error) {
+			var vals []any
+			for i, p := range export.DecodeList(m.OutcomePrices) {
+				d, ok := parseDecimal(p)
+				if !ok {
+					return nil, fmt.Errorf("market %s: price %d %q is not a decimal", m.ID, i, p)
+				}
+				vals = append(vals, d)
+			}
+			return vals, nil
+		},
+	},
+	repeatedUTF8Column("tags", func(m gamma.Market) []string { return export.TagLabels(m.Tags) }),
+	repeatedUTF8Column("categories", func(m gamma.Market) []string { return export.CategoryLabels(m.Categories) }),
+	repeatedUTF8Column("clob_token_ids", func(m gamma.Market) []string { return export.DecodeList(m.CLOBTokenIDs) }),
+}
+
+var eventColumns = []column[gamma.Event]{
+	utf8Column("id", func(e gamma.Event) string { return e.ID }),
+	utf8Column("ticker", func(e gamma.Event) string { return e.Ticker }),
+	utf8Column("slug", func(e gamma.Event) string { return e.Slug }),
+	utf8Column("title", func(e gamma.Event) string { return e.Title }),
+	utf8Column("category", func(e gamma.Event) string { return e.Category }),
+	utf8Column("subcategory", func(e gamma.Event) string { return e.Subcategory }),
+	boolColumn("active", func(e gamma.Event) bool { return e.Active }),
+	boolColumn("neg_risk", func(e gamma.Event) bool { return e.NegRisk }),
+	timestampColumn("start_date", func(e gamma.Event) time.Time { return e.StartDate }),
+	timestampColumn("end_date", func(e gamma.Event) time.Time { return e.EndDate }),
+	timestampColumn("created_at", func(e gamma.Event) time.Time { return e.CreatedAt }),
+	timestampColumn("updated_at", func(e gamma.Event) time.Time { return e.UpdatedAt }),
+	doubleColumn("volume", func(e gamma.Event) float64 { return e.Volume }),
+	doubleColumn("volume_24hr", func(e gamma.Event) float64 { return e.Volume24hr }),
+	doubleColumn("liquidity", func(e gamma.Event) float64 { return e.Liquidity }),
+	optionalUTF8Column("neg_risk_market_id", func(e gamma.Event) string { return e.NegRiskMarketID }),
+	repeatedUTF8Column("market_ids", func(e gamma.Event) []string {
+		ids := make([]string, len(e.Markets))
+		for i, m := range e.Markets {
+			ids[i] = m.ID
+		}
+		return ids
+	}),
+	repeatedUTF8Column("tags", func(e gamma.Event) []string { return export.TagLabels(e.Tags) }),
+	repeatedUTF8Column("categories", func(e gamma.Event) []string { return export.CategoryLabels(e.Categories) }),
+	repeatedUTF8Column("series", func(e gamma.Event) []string {
+		titles := make([]string, len(e.Series))
+		for i, s := range e.Series {
+			titles[i] = s.Title
+		}
+		return titles
+	}),
+}
+
+func utf8Column[T any](name string, fn func(T) string) column[T] {
+	return column[T]{name, typeByteArray, required, convUTF8, func(v T) ([]any, error) { return []any{fn(v)}, nil }}
+}
+
+// optionalUTF8Column stores empty strings as null
+func optionalUTF8Column[T any](name string, fn func(T) string) column[T] {
+	return column[T]{name, typeByteArray, optional, convUTF8, func(v T) ([]any, error) {
+		if s := fn(v); s != "" {
+			return []any{s}, nil
+		}
+		return nil, nil
+	}}
+}
+
+func repeatedUTF8Column[T any](name string, fn func(T) []string) column[T] {
+	return column[T]{name, typeByteArray, repeated, convUTF8, func(v T) ([]any, error) {
+		list := fn(v)
+		vals := make([]any, len(list))
+		for i, s := range list {
+			vals[i] = s
+		}
+		return vals, nil
+	}}
+}
+
+func boolColumn[T any](name string, fn func(T) bool) column[T] {
+	return column[T]{name, typeBoolean, required, convNone, func(v T) ([]any, error) { return []any{fn(v)}, nil }}
+}
+
+func doubleColumn[T any](name string, fn func(T) float64) column[T] {
+	return column[T]{name, typeDouble, required, convNone, func(v T) ([]any, error) { return []any{fn(v)}, nil }}
+}
+
+// timestampColumn stores zero times as null
+func timestampColumn[T any](name string, fn func(T) time.Time) column[T] {
+	return column[T]{name, typeInt64, optional, convTimestampMillis, func(v T) ([]any, error) {
+		if t := fn(v); !t.IsZero() {
+			return []any{t.UnixMilli()}, nil
+		}
+		return nil, nil
+	}}
+}
+
+// decimalColumn parses decimal strings, storing unparseable values as null
+func decimalColumn[T any](name string, fn func(T) string) column[T] {
+	return column[T]{name, typeInt64, optional, convDecimal, func(v T) ([]any, error) {
+		if d, ok := parseDecimal(fn(v)); ok {
+			return []any{d}, nil
+		}
+		return nil, nil
+	}}
+}
+
+// parseDecimal parses a decimal string into an integer scaled by DecimalScale
+// Digits past the scale are truncated. It is exact, unlike going through float64
+func parseDecimal(s string) (int64, bool) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, false
+	}
+	if len(frac) > DecimalScale {
+		frac = frac[:DecimalScale]
+	}
+	frac += strings.Repeat("0", DecimalScale-len(frac))
+
+	digits := whole + frac
+	if len(digits) > DecimalPrecision {
+		return 0, false
+	}
+
+	var n int64
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+		n = n*10 + int64(r-'0')
+	}
+	if neg {
+		n = -n
+	}
+	return n, true
+}
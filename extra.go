@@ -0,0 +1,268 @@
+// gammago/extra.go
+
+package gammago
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Gamma adds fields often. The models keep anything they don't declare in
+// Extra and write it back out on marshal, so storing and re-serialising a
+// record loses nothing
+
+// fieldCache caches structFields for each model type
+var fieldCache sync.Map // reflect.Type -> map[string]structField
+
+func cachedFields(t reflect.Type) map[string]structField {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.(map[string]structField)
+	}
+	fields := structFields(t)
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// decodeWithExtra decodes data into v, a pointer to a model, and returns the
+// keys v doesn't declare. The object is parsed once and each declared field
+// is decoded from its own value, so errors name the model and field. Keys in
+// custom, lower-cased, go to the given values instead of v's fields
+func decodeWithExtra(data []byte, v any, custom map[string]any) (map[string]json.RawMessage, error) {
+	rv := reflect.ValueOf(v).Elem()
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		var te *json.UnmarshalTypeError
+		if errors.As(err, &te) {
+			te.Type = rv.Type()
+		}
+		return nil, err
+	}
+
+	fields := cachedFields(rv.Type())
+	var extra map[string]json.RawMessage
+	var firstErr error
+	for _, k := range slices.Sorted(maps.Keys(all)) {
+		key := strings.ToLower(k)
+		f, ok := fields[key]
+		if !ok {
+			if extra == nil {
+				extra = map[string]json.RawMessage{}
+			}
+			extra[k] = all[k]
+			continue
+		}
+		// like encoding/json, an exact match beats a case-insensitive one
+		if _, exact := all[f.name]; exact && k != f.name {
+			continue
+		}
+		dst, ok := custom[key]
+		if !ok {
+			dst = rv.FieldByIndex(f.index).Addr().Interface()
+		}
+		if err := json.Unmarshal(all[k], dst); err != nil && firstErr == nil {
+			firstErr = fieldError(rv.Type(), f.name, err)
+		}
+	}
+	return extra, firstErr
+}
+
+// fieldError adds the model and field to a type error from decoding one
+// field, as encoding/json does for fields it decodes itself
+func fieldError(t reflect.Type, name string, err error) error {
+	var te *json.UnmarshalTypeError
+	if !errors.As(err, &te) {
+		return err
+	}
+	if te.Field != "" {
+		name += "." + te.Field
+	}
+	te.Struct, te.Field = t.Name(), name
+	return te
+}
+
+// encodeWithExtra encodes v, an alias of a model, and appends
+// the extra keys in sorted order. Declared fields win over extra keys with
+// the same name
+func encodeWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	fields := cachedFields(reflect.TypeOf(v))
+	names := make([]string, 0, len(extra))
+	for k := range extra {
+		if _, ok := fields[strings.ToLower(k)]; !ok {
+			names = append(names, k)
+		}
+	}
+	slices.Sort(names)
+
+	buf := bytes.NewBuffer(data[:len(data)-1]) // drop the closing brace
+	for _, k := range names {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		if err := json.Compact(buf, extra[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// The aliases drop the methods below so encoding/json doesn't recurse
+type (
+	teamAlias        Team
+	sportAlias       Sport
+	marketTypesAlias MarketTypes
+	tagAlias         Tag
+	categoryAlias    Category
+	chatAlias        Chat
+	collectionAlias  Collection
+	marketAlias      Market
+	seriesAlias      Series
+	eventAlias       Event
+	profileAlias     Profile
+)
+
+func (t *Team) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, t, nil)
+	t.Extra = extra
+	return err
+}
+
+func (t Team) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(teamAlias(t), t.Extra)
+}
+
+func (s *Sport) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, s, nil)
+	s.Extra = extra
+	return err
+}
+
+func (s Sport) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(sportAlias(s), s.Extra)
+}
+
+func (m *MarketTypes) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, m, nil)
+	m.Extra = extra
+	return err
+}
+
+func (m MarketTypes) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(marketTypesAlias(m), m.Extra)
+}
+
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, t, nil)
+	t.Extra = extra
+	return err
+}
+
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(tagAlias(t), t.Extra)
+}
+
+func (c *Category) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, c, nil)
+	c.Extra = extra
+	return err
+}
+
+func (c Category) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(categoryAlias(c), c.Extra)
+}
+
+func (c *Chat) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, c, nil)
+	c.Extra = extra
+	return err
+}
+
+func (c Chat) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(chatAlias(c), c.Extra)
+}
+
+func (c *Collection) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, c, nil)
+	c.Extra = extra
+	return err
+}
+
+func (c Collection) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(collectionAlias(c), c.Extra)
+}
+
+// Gamma writes closedTime as "2006-01-02 15:04:05+00" rather than RFC 3339,
+// so it is decoded by hand
+func (m *Market) UnmarshalJSON(data []byte) error {
+	var closedTime string
+	extra, err := decodeWithExtra(data, m, map[string]any{"closedtime": &closedTime})
+	m.Extra = extra
+	// the other fields are decoded even if one fails, so closedTime is too
+	var timeErr error
+	m.ClosedTime, timeErr = parseTime(closedTime)
+	return errors.Join(err, timeErr)
+}
+
+func (m Market) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(marketAlias(m), m.Extra)
+}
+
+func (s *Series) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, s, nil)
+	s.Extra = extra
+	return err
+}
+
+func (s Series) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(seriesAlias(s), s.Extra)
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, e, nil)
+	e.Extra = extra
+	return err
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(eventAlias(e), e.Extra)
+}
+
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	extra, err := decodeWithExtra(data, p, nil)
+	p.Extra = extra
+	return err
+}
+
+func (p Profile) MarshalJSON() ([]byte, error) {
+	return encodeWithExtra(profileAlias(p), p.Extra)
+}
+
+// parseTime reads the timestamp layouts Gamma uses. "" is the zero time
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	var err error
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07", "2006-01-02 15:04:05.999999999Z07:00", time.DateOnly} {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
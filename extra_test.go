@@ -0,0 +1,127 @@
+// gammago/extra_test.go
+
+package gammago
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExtraRoundTrip(t *testing.T) {
+	in := `{"id":"501","question":"Will it rain?","active":true,"cyom":false,"rfqEnabled":false,
+		"rewardsMinSize":50,"negRiskAugmented":true,"Slug":"rain",
+		"events":[{"id":"9","title":"Weather","enableNegRisk":true}],
+		"tags":[{"id":"2","label":"Weather","forceShow":true}]}`
+
+	var m Market
+	if err := json.Unmarshal([]byte(in), &m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if m.ID != "501" || !m.Active || m.Slug != "rain" {
+		t.Errorf("declared fields not decoded: %+v", m)
+	}
+	if len(m.Extra) != 4 || string(m.Extra["rewardsMinSize"]) != "50" || string(m.Extra["cyom"]) != "false" {
+		t.Errorf("Extra = %v", m.Extra)
+	}
+	if _, ok := m.Extra["Slug"]; ok {
+		t.Error("keys matching a field case-insensitively should not be extra")
+	}
+	if string(m.Events[0].Extra["enableNegRisk"]) != "true" || string(m.Tags[0].Extra["forceShow"]) != "true" {
+		t.Errorf("nested extras lost: %v %v", m.Events[0].Extra, m.Tags[0].Extra)
+	}
+
+	out, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var again Market
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("unmarshal re-encoded: %v", err)
+	}
+	if len(again.Extra) != 4 || string(again.Extra["negRiskAugmented"]) != "true" || string(again.Events[0].Extra["enableNegRisk"]) != "true" {
+		t.Errorf("extras not re-emitted: %s", out)
+	}
+
+	out2, _ := json.Marshal(again)
+	if string(out) != string(out2) {
+		t.Errorf("encoding is not stable:\n%s\n%s", out, out2)
+	}
+}
+
+func TestExtraEdges(t *testing.T) {
+	t.Run("no extras", func(t *testing.T) {
+		var tag Tag
+		json.Unmarshal([]byte(`{"id":"1","label":"a","slug":"a"}`), &tag)
+		if tag.Extra != nil {
+			t.Errorf("Extra = %v, want nil", tag.Extra)
+		}
+		out, _ := json.Marshal(tag)
+		if string(out) != `{"id":"1","label":"a","slug":"a"}` {
+			t.Errorf("marshal = %s", out)
+		}
+	})
+
+	t.Run("declared fields win", func(t *testing.T) {
+		tag := Tag{ID: "1", Extra: map[string]json.RawMessage{"id": json.RawMessage(`"2"`), "b": json.RawMessage(`[1, 2]`), "a": json.RawMessage(`{}`)}}
+		out, err := json.Marshal(tag)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		if string(out) != `{"id":"1","label":"","slug":"","a":{},"b":[1,2]}` {
+			t.Errorf("marshal = %s", out)
+		}
+	})
+
+	t.Run("empty declared struct", func(t *testing.T) {
+		out, err := encodeWithExtra(struct{}{}, map[string]json.RawMessage{"x": json.RawMessage(`1`)})
+		if err != nil || string(out) != `{"x":1}` {
+			t.Errorf("encodeWithExtra = %s, %v", out, err)
+		}
+	})
+
+	t.Run("bad JSON", func(t *testing.T) {
+		var e Event
+		if err := json.Unmarshal([]byte(`{"id":`), &e); err == nil {
+			t.Error("expected an error")
+		}
+		if err := json.Unmarshal([]byte(`{"id":5}`), &e); err == nil {
+			t.Error("expected a type error")
+		}
+	})
+
+	t.Run("errors name the model", func(t *testing.T) {
+		tests := []struct {
+			json string
+			v    any
+			want string
+		}{
+			{`{"id":5}`, &Event{}, "json: cannot unmarshal number into Go struct field Event.id of type string"},
+			{`{"tags":[{"slug":5}]}`, &Market{}, "json: cannot unmarshal number into Go struct field Market.tags.slug of type string"},
+			{`{"markets":[{"closedTime":"soon"}]}`, &Event{}, `parsing time "soon" as "2006-01-02": cannot parse "soon" as "2006"`},
+			{`[1]`, &Tag{}, "json: cannot unmarshal array into Go value of type gammago.Tag"},
+		}
+		for _, tt := range tests {
+			err := json.Unmarshal([]byte(tt.json), tt.v)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %s", tt.json, err, tt.want)
+			}
+		}
+	})
+
+	t.Run("other fields still decode", func(t *testing.T) {
+		var m Market
+		err := json.Unmarshal([]byte(`{"id":"1","question":5,"slug":"a","cyom":true,"closedTime":"2026-03-01 12:00:00+00"}`), &m)
+		if err == nil || m.ID != "1" || m.Slug != "a" || string(m.Extra["cyom"]) != "true" {
+			t.Errorf("got %+v, %v", m, err)
+		}
+		if want := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC); !m.ClosedTime.Equal(want) {
+			t.Errorf("ClosedTime = %v, want %v", m.ClosedTime, want)
+		}
+		var te *json.UnmarshalTypeError
+		if !errors.As(err, &te) || te.Field != "question" {
+			t.Errorf("expected the question type error, got %v", err)
+		}
+	})
+}
@@ -3,9 +3,11 @@
 package gammago
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -30,7 +32,7 @@ func GetTeams(limit, offset int, league, name, abbreviation []string) ([]Team, e
 
 	reqUrl, _ := buildUrl("teams", params)
 
-	return genericGet[[]Team](reqUrl)
+	return genericGet[[]Team]("/teams", reqUrl)
 }
 
 // GetSports gets all sports
@@ -40,7 +42,7 @@ func GetSports() ([]Sport, error) {
 
 	reqUrl, _ := buildUrl("sports", params)
 
-	return genericGet[[]Sport](reqUrl)
+	return genericGet[[]Sport]("/sports", reqUrl)
 }
 
 // GetMarketTypes gets market types
@@ -50,7 +52,7 @@ func GetMarketTypes() (MarketTypes, error) {
 
 	reqUrl, _ := buildUrl("sports/market-types", params)
 
-	return genericGet[MarketTypes](reqUrl)
+	return genericGet[MarketTypes]("/sports/market-types", reqUrl)
 }
 
 // GetTags gets tags with pagination
@@ -62,7 +64,7 @@ func GetTags(limit, offset int) ([]Tag, error) {
 
 	reqUrl, _ := buildUrl("tags", params)
 
-	return genericGet[[]Tag](reqUrl)
+	return genericGet[[]Tag]("/tags", reqUrl)
 }
 
 // GetTagBySlug gets a specific tag by its slug
@@ -71,7 +73,7 @@ func GetTagBySlug(slug string) (Tag, error) {
 	params.Add("order", "id")
 
 	reqUrl, _ := buildUrl(fmt.Sprintf("tags/slug/%s", slug), params)
-	return genericGet[Tag](reqUrl)
+	return genericGet[Tag]("/tags/slug/{slug}", reqUrl)
 }
 
 // GetRelatedTagsBySlug gets related tags for a given tag slug
@@ -80,24 +82,56 @@ func GetRelatedTagsByTagId(id int) ([]Tag, error) {
 	params.Add("order", "id")
 
 	reqUrl, _ := buildUrl(fmt.Sprintf("tags/%d/related-tags/tags", id), params)
-	return genericGet[[]Tag](reqUrl)
+	return genericGet[[]Tag]("/tags/{id}/related-tags/tags", reqUrl)
+}
+
+// GetSeries gets series with pagination
+func GetSeries(limit, offset int) ([]Series, error) {
+	params := url.Values{}
+	params.Add("order", "id")
+	params.Add("limit", strconv.Itoa(limit))
+	params.Add("offset", strconv.Itoa(offset))
+
+	reqUrl, _ := buildUrl("series", params)
+	return genericGet[[]Series]("/series", reqUrl)
 }
 
 // GetEventsByTag gets events by tag ID
 func GetEventsByTag(tagID int, includeRelated bool) ([]Event, error) {
+	return GetEventsByTagCtx(context.Background(), tagID, includeRelated)
+}
+
+// GetEventsByTagCtx is GetEventsByTag with a context for cancellation
+func GetEventsByTagCtx(ctx context.Context, tagID int, includeRelated bool) ([]Event, error) {
 	params := url.Values{}
 	params.Add("order", "id")
 	params.Add("tag_id", strconv.Itoa(tagID))
 	params.Add("related_tags", strconv.FormatBool(includeRelated))
 
 	reqUrl, _ := buildUrl("events", params)
-	return genericGet[[]Event](reqUrl)
+	return genericGetCtx[[]Event](ctx, "/events", reqUrl)
 }
 
 // GetEventByID gets events by their IDs
 func GetEventByID(id string) (Event, error) {
+	return GetEventByIDCtx(context.Background(), id)
+}
+
+// GetEventByIDCtx is GetEventByID with a context for cancellation
+func GetEventByIDCtx(ctx context.Context, id string) (Event, error) {
 	reqUrl, _ := buildUrl(fmt.Sprintf("events/%s", id), nil)
-	return genericGet[Event](reqUrl)
+	return genericGetCtx[Event](ctx, "/events/{id}", reqUrl)
+}
+
+// GetEventBySlug gets an event by its slug
+func GetEventBySlug(slug string) (Event, error) {
+	return GetEventBySlugCtx(context.Background(), slug)
+}
+
+// GetEventBySlugCtx is GetEventBySlug with a context for cancellation
+func GetEventBySlugCtx(ctx context.Context, slug string) (Event, error) {
+	reqUrl, _ := buildUrl(fmt.Sprintf("events/slug/%s", slug), nil)
+	return genericGetCtx[Event](ctx, "/events/slug/{slug}", reqUrl)
 }
 
 // GetEventsBeforeDate gets ALL events ending before a specific date
@@ -128,7 +162,7 @@ func GetEventsBeforeDate(
 	}
 
 	reqUrl, _ := buildUrl("events", params)
-	return genericGet[[]Event](reqUrl)
+	return genericGet[[]Event]("/events", reqUrl)
 }
 
 // GetEventsBetweenDates gets events starting and ending between two dates
@@ -141,6 +175,40 @@ func GetEventsBetweenDates(
 	startDate time.Time,
 	status Status,
 ) ([]Event, error) {
+	params := eventsBetweenDatesParams(limit, offset, volumeMin, tagId, endDate, startDate, status)
+
+	reqUrl, _ := buildUrl("events", params)
+	return genericGet[[]Event]("/events", reqUrl)
+}
+
+// StreamEventsBetweenDates is GetEventsBetweenDates, but each event is passed to fn
+// as soon as it is decoded instead of holding the whole page in memory
+// Returning an error from fn stops the stream and returns that error
+func StreamEventsBetweenDates(
+	limit,
+	offset,
+	volumeMin,
+	tagId int,
+	endDate time.Time,
+	startDate time.Time,
+	status Status,
+	fn func(Event) error,
+) error {
+	params := eventsBetweenDatesParams(limit, offset, volumeMin, tagId, endDate, startDate, status)
+
+	reqUrl, _ := buildUrl("events", params)
+	return genericStream(context.Background(), "/events", reqUrl, fn)
+}
+
+func eventsBetweenDatesParams(
+	limit,
+	offset,
+	volumeMin,
+	tagId int,
+	endDate time.Time,
+	startDate time.Time,
+	status Status,
+) url.Values {
 	params := url.Values{}
 	params.Add("limit", strconv.Itoa(limit))
 	params.Add("offset", strconv.Itoa(offset))
@@ -160,29 +228,131 @@ func GetEventsBetweenDates(
 		params.Add("closed", "true")
 	}
 
-	reqUrl, _ := buildUrl("events", params)
-	return genericGet[[]Event](reqUrl)
+	return params
 }
 
 // GetMarketsBetweenDates gets markets between specified dates
 func GetMarketsBetweenDates(limit, offset int, startDate, endDate time.Time) ([]Market, error) {
+	reqUrl, _ := buildUrl("markets", marketsBetweenDatesParams(limit, offset, startDate, endDate))
+	return genericGet[[]Market]("/markets", reqUrl)
+}
+
+// StreamMarketsBetweenDates is GetMarketsBetweenDates, but each market is passed to fn
+// as soon as it is decoded instead of holding the whole page in memory
+// Returning an error from fn stops the stream and returns that error
+func StreamMarketsBetweenDates(limit, offset int, startDate, endDate time.Time, fn func(Market) error) error {
+	reqUrl, _ := buildUrl("markets", marketsBetweenDatesParams(limit, offset, startDate, endDate))
+	return genericStream(context.Background(), "/markets", reqUrl, fn)
+}
+
+func marketsBetweenDatesParams(limit, offset int, startDate, endDate time.Time) url.Values {
 	params := url.Values{}
 	params.Add("order", "id")
 	params.Add("limit", strconv.Itoa(limit))
 	params.Add("offset", strconv.Itoa(offset))
 	params.Add("start_date_min", startDate.Format("2006-01-02T15:04:05Z"))
 	params.Add("end_date_max", endDate.Format("2006-01-02T15:04:05Z"))
+	return params
+}
 
-	reqUrl, _ := buildUrl("markets", params)
-	return genericGet[[]Market](reqUrl)
+// GetEventsByUpdatedAt gets events, most recently updated first
+func GetEventsByUpdatedAt(limit, offset int) ([]Event, error) {
+	reqUrl, _ := buildUrl("events", updatedAtParams(limit, offset))
+	return genericGet[[]Event]("/events", reqUrl)
+}
+
+// GetMarketsByUpdatedAt gets markets, most recently updated first
+func GetMarketsByUpdatedAt(limit, offset int) ([]Market, error) {
+	reqUrl, _ := buildUrl("markets", updatedAtParams(limit, offset))
+	return genericGet[[]Market]("/markets", reqUrl)
+}
+
+func updatedAtParams(limit, offset int) url.Values {
+	params := url.Values{}
+	params.Add("order", "updatedAt")
+	params.Add("ascending", "false")
+	params.Add("limit", strconv.Itoa(limit))
+	params.Add("offset", strconv.Itoa(offset))
+	return params
 }
 
 // GetMarketByID gets a market by its ID
 func GetMarketByID(marketID int) ([]Market, error) {
+	return GetMarketByIDCtx(context.Background(), marketID)
+}
+
+// GetMarketByIDCtx is GetMarketByID with a context for cancellation
+func GetMarketByIDCtx(ctx context.Context, marketID int) ([]Market, error) {
 	params := url.Values{}
 	params.Add("order", "id")
 	params.Add("id", strconv.Itoa(marketID))
 
 	reqUrl, _ := buildUrl("markets", params)
-	return genericGet[[]Market](reqUrl)
+	return genericGetCtx[[]Market](ctx, "/markets", reqUrl)
+}
+
+// GetMarketsByConditionIDs gets the markets with the given condition IDs
+// Markets Gamma doesn't know are left out; no IDs means no markets
+func GetMarketsByConditionIDs(conditionIDs ...string) ([]Market, error) {
+	return GetMarketsByConditionIDsCtx(context.Background(), conditionIDs...)
+}
+
+// GetMarketsByConditionIDsCtx is GetMarketsByConditionIDs with a context for cancellation
+func GetMarketsByConditionIDsCtx(ctx context.Context, conditionIDs ...string) ([]Market, error) {
+	// without condition_ids the query would match every market
+	if len(conditionIDs) == 0 {
+		return nil, nil
+	}
+
+	params := url.Values{}
+	params.Add("limit", strconv.Itoa(len(conditionIDs)))
+	for _, id := range conditionIDs {
+		params.Add("condition_ids", id)
+	}
+
+	reqUrl, _ := buildUrl("markets", params)
+	return genericGetCtx[[]Market](ctx, "/markets", reqUrl)
+}
+
+// GetProfileByAddress gets the public profile of a wallet address
+func GetProfileByAddress(address string) (Profile, error) {
+	if !isAddress(address) {
+		return Profile{}, fmt.Errorf("invalid address %q", address)
+	}
+	params := url.Values{}
+	params.Add("address", address)
+
+	reqUrl, _ := buildUrl("public-profile", params)
+	return genericGet[Profile]("/public-profile", reqUrl)
+}
+
+// SearchProfiles lists public profiles matching query, at most limit of them
+func SearchProfiles(query string, limit int) ([]Profile, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("empty profile search")
+	}
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("search_profiles", "true")
+	params.Add("search_tags", "false")
+	params.Add("limit_per_type", strconv.Itoa(limit))
+
+	reqUrl, _ := buildUrl("public-search", params)
+	res, err := genericGet[struct {
+		Profiles []Profile `json:"profiles"`
+	}]("/public-search", reqUrl)
+	return res.Profiles, err
+}
+
+// isAddress reports whether s looks like a 0x-prefixed 20-byte hex address
+func isAddress(s string) bool {
+	if len(s) != 42 || (s[:2] != "0x" && s[:2] != "0X") {
+		return false
+	}
+	for _, c := range s[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
 }
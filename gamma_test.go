@@ -3,6 +3,8 @@
 package gammago
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"testing"
@@ -171,3 +173,88 @@ func TestGammaEndpoints(t *testing.T) {
 		}
 	})
 }
+
+func TestProfiles(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	const wallet = "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/public-profile":
+			w.Write([]byte(`{"proxyWallet":"` + r.URL.Query().Get("address") + `","name":"alice","pseudonym":"Bright-Owl",
+				"displayUsernamePublic":true,"bio":"hi","verifiedBadge":true,"createdAt":"2024-05-01T10:00:00Z"}`))
+		case "/public-search":
+			w.Write([]byte(`{"events":[],"profiles":[{"proxyWallet":"0x1","pseudonym":"Quiet-Fox"},{"proxyWallet":"0x2","name":"bob"}]}`))
+		}
+	}))
+	defer srv.Close()
+	SetBaseURL(srv.URL)
+	defer SetBaseURL("")
+
+	t.Run("GetProfileByAddress", func(t *testing.T) {
+		p, err := GetProfileByAddress(wallet)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.ProxyWallet != wallet || p.DisplayName() != "alice" || !p.VerifiedBadge || p.CreatedAt.Year() != 2024 {
+			t.Errorf("unexpected profile %+v", p)
+		}
+
+		for _, bad := range []string{"", "0x123", "56687bf447db6ffa42ffe2204a05edaa20f5583900", "0x56687bf447db6ffa42ffe2204a05edaa20f5583z"} {
+			if _, err := GetProfileByAddress(bad); err == nil {
+				t.Errorf("GetProfileByAddress(%q) should fail", bad)
+			}
+		}
+	})
+
+	t.Run("SearchProfiles", func(t *testing.T) {
+		profiles, err := SearchProfiles("fox", 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(profiles) != 2 || profiles[0].DisplayName() != "Quiet-Fox" || profiles[1].DisplayName() != "0x2" {
+			t.Errorf("unexpected profiles %+v", profiles)
+		}
+		q, _ := url.ParseQuery(queries[len(queries)-1][len("/public-search?"):])
+		if q.Get("q") != "fox" || q.Get("search_profiles") != "true" || q.Get("limit_per_type") != "5" {
+			t.Errorf("unexpected query %v", q)
+		}
+
+		if _, err := SearchProfiles("  ", 5); err == nil {
+			t.Error("empty search should fail")
+		}
+	})
+}
+
+func TestGetMarketsByConditionIDs(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`[{"id":"501","conditionId":"0xc1"}]`))
+	}))
+	defer srv.Close()
+	SetBaseURL(srv.URL)
+	defer SetBaseURL("")
+
+	markets, err := GetMarketsByConditionIDs()
+	if err != nil || markets != nil {
+		t.Errorf("no IDs: got %v, %v", markets, err)
+	}
+	if len(queries) != 0 {
+		t.Errorf("no IDs should make no request, got %q", queries)
+	}
+
+	markets, err = GetMarketsByConditionIDs("0xc1", "0xc2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(markets) != 1 || len(queries) != 1 || queries[0] != "condition_ids=0xc1&condition_ids=0xc2&limit=2" {
+		t.Errorf("unexpected markets %+v, queries %q", markets, queries)
+	}
+}
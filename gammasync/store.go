@@ -0,0 +1,357 @@
+// gammago/gammasync/store.go
+
+package gammasync
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// Kind is the type of record held in the store
+type Kind string
+
+const (
+	KindEvent  Kind = "event"
+	KindMarket Kind = "market"
+	KindTag    Kind = "tag"
+	KindSeries Kind = "series"
+)
+
+// Op is what happened to a record
+type Op string
+
+const (
+	OpPut     Op = "put"
+	OpClosed  Op = "closed"
+	OpDeleted Op = "deleted"
+)
+
+// Change is a recorded closure or deletion
+type Change struct {
+	Kind Kind      `json:"kind"`
+	ID   string    `json:"id"`
+	Op   Op        `json:"op"`
+	At   time.Time `json:"at"`
+}
+
+// record is one line of the store file
+// Watermark lines have Op "watermark" and no ID
+type record struct {
+	Kind      Kind            `json:"kind"`
+	ID        string          `json:"id,omitempty"`
+	Op        Op              `json:"op"`
+	At        time.Time       `json:"at"`
+	Watermark time.Time       `json:"watermark,omitzero"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+const opWatermark Op = "watermark"
+
+// Store is a local copy of Gamma records kept in a single append-only
+// JSON lines file. The whole file is replayed into memory on Open
+// Use Compact to drop superseded lines. A Store is safe for concurrent use
+type Store struct {
+	mu         sync.RWMutex
+	path       string
+	f          *os.File
+	w          *bufio.Writer
+	records    map[Kind]map[string]json.RawMessage
+	watermarks map[Kind]time.Time
+	changes    []Change
+}
+
+// Open opens or creates the store file at path
+// A torn final line left by a crash is discarded
+func Open(path string) (*Store, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		path:       path,
+		f:          f,
+		records:    make(map[Kind]map[string]json.RawMessage),
+		watermarks: make(map[Kind]time.Time),
+	}
+
+	good, err := s.replay(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Truncate(good); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(good, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	s.w = bufio.NewWriter(f)
+	return s, nil
+}
+
+// replay applies every line and returns the offset after the last good one
+func (s *Store) replay(r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+	var offset int64
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var rec record
+			if jsonErr := json.Unmarshal(line, &rec); jsonErr != nil {
+				return offset, fmt.Errorf("gammasync: corrupt record at offset %d: %w", offset, jsonErr)
+			}
+			s.apply(rec)
+			offset += int64(len(line))
+		}
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+	}
+}
+
+func (s *Store) apply(rec record) {
+	switch rec.Op {
+	case opWatermark:
+		s.watermarks[rec.Kind] = rec.Watermark
+	case OpPut:
+		m, ok := s.records[rec.Kind]
+		if !ok {
+			m = make(map[string]json.RawMessage)
+			s.records[rec.Kind] = m
+		}
+		m[rec.ID] = rec.Data
+	case OpDeleted:
+		delete(s.records[rec.Kind], rec.ID)
+		s.changes = append(s.changes, Change{rec.Kind, rec.ID, rec.Op, rec.At})
+	case OpClosed:
+		s.changes = append(s.changes, Change{rec.Kind, rec.ID, rec.Op, rec.At})
+	}
+}
+
+// append writes and applies a record; the caller holds the lock
+func (s *Store) append(rec record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	s.apply(rec)
+	return nil
+}
+
+// Flush writes buffered records and syncs the file to disk
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+func (s *Store) flush() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+// Close flushes and closes the store file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.flush(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
+
+// Compact rewrites the file with one line per live record, watermark and change
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".compact"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+
+	write := func(rec record) error { return enc.Encode(rec) }
+	err = s.each(write)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		tmp.Close()
+		return err
+	}
+	s.f.Close()
+	s.f = tmp
+	s.w = bufio.NewWriter(tmp)
+	return nil
+}
+
+// each emits the current state as records in a stable order
+// Changes come first: replaying a deletion before the puts keeps a record
+// that was deleted and later re-created, as the original log order would
+func (s *Store) each(fn func(record) error) error {
+	for _, c := range s.changes {
+		if err := fn(record{Kind: c.Kind, ID: c.ID, Op: c.Op, At: c.At}); err != nil {
+			return err
+		}
+	}
+	kinds := []Kind{KindEvent, KindMarket, KindTag, KindSeries}
+	for _, k := range kinds {
+		if wm, ok := s.watermarks[k]; ok {
+			if err := fn(record{Kind: k, Op: opWatermark, Watermark: wm}); err != nil {
+				return err
+			}
+		}
+		for _, id := range sortedIDs(s.records[k]) {
+			if err := fn(record{Kind: k, ID: id, Op: OpPut, Data: s.records[k][id]}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Watermark is the latest updatedAt seen for kind, or zero before the first sync
+func (s *Store) Watermark(kind Kind) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watermarks[kind]
+}
+
+func (s *Store) setWatermark(kind Kind, t time.Time) error {
+	return s.append(record{Kind: kind, Op: opWatermark, At: time.Now().UTC(), Watermark: t})
+}
+
+// Changes returns closures and deletions recorded at or after since, oldest first
+func (s *Store) Changes(since time.Time) []Change {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Change
+	for _, c := range s.changes {
+		if !c.At.Before(since) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Event gets a stored event by ID
+func (s *Store) Event(id string) (gamma.Event, bool) {
+	return get[gamma.Event](s, KindEvent, id)
+}
+
+// Events returns stored events matching fn, ordered by ID
+// A nil fn matches everything
+func (s *Store) Events(fn func(gamma.Event) bool) []gamma.Event {
+	return list(s, KindEvent, fn)
+}
+
+// Market gets a stored market by ID
+func (s *Store) Market(id string) (gamma.Market, bool) {
+	return get[gamma.Market](s, KindMarket, id)
+}
+
+// Markets returns stored markets matching fn, ordered by ID
+// A nil fn matches everything
+func (s *Store) Markets(fn func(gamma.Market) bool) []gamma.Market {
+	return list(s, KindMarket, fn)
+}
+
+// Tags returns stored tags matching fn, ordered by ID
+// A nil fn matches everything
+func (s *Store) Tags(fn func(gamma.Tag) bool) []gamma.Tag {
+	return list(s, KindTag, fn)
+}
+
+// Series returns stored series matching fn, ordered by ID
+// A nil fn matches everything
+func (s *Store) Series(fn func(gamma.Series) bool) []gamma.Series {
+	return list(s, KindSeries, fn)
+}
+
+func get[T any](s *Store, kind Kind, id string) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var v T
+	data, ok := s.records[kind][id]
+	if !ok {
+		return v, false
+	}
+	return v, json.Unmarshal(data, &v) == nil
+}
+
+func list[T any](s *Store, kind Kind, fn func(T) bool) []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []T
+	for _, id := range sortedIDs(s.records[kind]) {
+		var v T
+		if err := json.Unmarshal(s.records[kind][id], &v); err != nil {
+			continue
+		}
+		if fn == nil || fn(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// sortedIDs orders numeric IDs numerically, then everything else lexically
+func sortedIDs(m map[string]json.RawMessage) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) && isDigits(ids[i]) && isDigits(ids[j]) {
+			return len(ids[i]) < len(ids[j])
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func isDigits(s string) bool {
+	return s != "" && bytes.IndexFunc([]byte(s), func(r rune) bool { return r < '0' || r > '9' }) < 0
+}
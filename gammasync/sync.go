@@ -0,0 +1,252 @@
+// gammago/gammasync/sync.go
+
+// Package gammasync mirrors Gamma events, markets, tags and series into a
+// local file so consumers can query them without re-pulling full pages
+//
+// Events and markets are fetched newest-updated first and only records
+// changed since the last run's updatedAt watermark are written. Tags and
+// series have no watermark and are re-pulled in full, which also lets the
+// syncer notice deletions. Closures are recorded when an event or market
+// turns closed; Gamma leaves active set on closed records, so it isn't used
+package gammasync
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// DefaultPageSize is used when Syncer.PageSize is 0
+const DefaultPageSize = 500
+
+// Fetcher is the subset of the Gamma API the syncer uses
+// APIFetcher calls the live API; tests substitute their own
+type Fetcher interface {
+	EventsByUpdatedAt(limit, offset int) ([]gamma.Event, error)
+	MarketsByUpdatedAt(limit, offset int) ([]gamma.Market, error)
+	Tags(limit, offset int) ([]gamma.Tag, error)
+	Series(limit, offset int) ([]gamma.Series, error)
+}
+
+// APIFetcher fetches from the Gamma API through the gammago package functions
+type APIFetcher struct{}
+
+func (APIFetcher) EventsByUpdatedAt(limit, offset int) ([]gamma.Event, error) {
+	return gamma.GetEventsByUpdatedAt(limit, offset)
+}
+
+func (APIFetcher) MarketsByUpdatedAt(limit, offset int) ([]gamma.Market, error) {
+	return gamma.GetMarketsByUpdatedAt(limit, offset)
+}
+
+func (APIFetcher) Tags(limit, offset int) ([]gamma.Tag, error) {
+	return gamma.GetTags(limit, offset)
+}
+
+func (APIFetcher) Series(limit, offset int) ([]gamma.Series, error) {
+	return gamma.GetSeries(limit, offset)
+}
+
+// Syncer copies changed records from Fetcher into Store
+type Syncer struct {
+	Store    *Store
+	Fetcher  Fetcher
+	PageSize int
+}
+
+// Result counts what one Run wrote
+type Result struct {
+	Events  int
+	Markets int
+	Tags    int
+	Series  int
+	Closed  int
+	Deleted int
+}
+
+// Run syncs every kind once and flushes the store
+// Watermarks only advance for kinds that synced without error
+func (s *Syncer) Run(ctx context.Context) (Result, error) {
+	var res Result
+	fetcher := s.Fetcher
+	if fetcher == nil {
+		fetcher = APIFetcher{}
+	}
+	pageSize := s.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	steps := []func() error{
+		func() error {
+			return syncUpdated(ctx, s.Store, KindEvent, pageSize, &res.Events, &res.Closed, fetcher.EventsByUpdatedAt,
+				func(e gamma.Event) (string, time.Time, bool) { return e.ID, e.UpdatedAt, e.Closed })
+		},
+		func() error {
+			return syncUpdated(ctx, s.Store, KindMarket, pageSize, &res.Markets, &res.Closed, fetcher.MarketsByUpdatedAt,
+				func(m gamma.Market) (string, time.Time, bool) { return m.ID, m.UpdatedAt, m.Closed })
+		},
+		func() error {
+			return syncFull(ctx, s.Store, KindTag, pageSize, &res.Tags, &res.Deleted, fetcher.Tags,
+				func(t gamma.Tag) string { return t.ID })
+		},
+		func() error {
+			return syncFull(ctx, s.Store, KindSeries, pageSize, &res.Series, &res.Deleted, fetcher.Series,
+				func(s gamma.Series) string { return s.ID })
+		},
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			s.Store.Flush()
+			return res, err
+		}
+	}
+	return res, s.Store.Flush()
+}
+
+// syncUpdated pages newest-updated first until it reaches the watermark
+func syncUpdated[T any](
+	ctx context.Context,
+	store *Store,
+	kind Kind,
+	pageSize int,
+	written, closed *int,
+	fetch func(limit, offset int) ([]T, error),
+	key func(T) (id string, updatedAt time.Time, closed bool),
+) error {
+	watermark := store.Watermark(kind)
+	newest := watermark
+	now := time.Now().UTC()
+
+	for offset := 0; ; offset += pageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := fetch(pageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		reached := false
+		store.mu.Lock()
+		for _, v := range page {
+			id, updatedAt, isClosed := key(v)
+			// records updated exactly at the watermark are rechecked,
+			// unchanged ones are skipped below
+			if updatedAt.Before(watermark) {
+				reached = true
+				break
+			}
+			if updatedAt.After(newest) {
+				newest = updatedAt
+			}
+
+			data, err := json.Marshal(v)
+			if err != nil {
+				store.mu.Unlock()
+				return err
+			}
+
+			wasClosed := false
+			prevData, seen := store.records[kind][id]
+			if seen {
+				if string(prevData) == string(data) {
+					continue
+				}
+				var prev T
+				if json.Unmarshal(prevData, &prev) == nil {
+					_, _, wasClosed = key(prev)
+				}
+			}
+
+			if err := store.append(record{Kind: kind, ID: id, Op: OpPut, At: now, Data: data}); err != nil {
+				store.mu.Unlock()
+				return err
+			}
+			*written++
+
+			if seen && !wasClosed && isClosed {
+				if err := store.append(record{Kind: kind, ID: id, Op: OpClosed, At: now}); err != nil {
+					store.mu.Unlock()
+					return err
+				}
+				*closed++
+			}
+		}
+		store.mu.Unlock()
+
+		if reached || len(page) < pageSize {
+			break
+		}
+	}
+
+	if newest.After(watermark) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.setWatermark(kind, newest)
+	}
+	return nil
+}
+
+// syncFull re-pulls every record and marks missing ones deleted
+func syncFull[T any](
+	ctx context.Context,
+	store *Store,
+	kind Kind,
+	pageSize int,
+	written, deleted *int,
+	fetch func(limit, offset int) ([]T, error),
+	id func(T) string,
+) error {
+	var all []T
+	for offset := 0; ; offset += pageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := fetch(pageSize, offset)
+		if err != nil {
+			return err
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	now := time.Now().UTC()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	seen := make(map[string]bool, len(all))
+	for _, v := range all {
+		key := id(v)
+		seen[key] = true
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if prev, ok := store.records[kind][key]; ok && string(prev) == string(data) {
+			continue // unchanged, don't grow the file
+		}
+		if err := store.append(record{Kind: kind, ID: key, Op: OpPut, At: now, Data: data}); err != nil {
+			return err
+		}
+		*written++
+	}
+
+	for _, key := range sortedIDs(store.records[kind]) {
+		if !seen[key] {
+			if err := store.append(record{Kind: kind, ID: key, Op: OpDeleted, At: now}); err != nil {
+				return err
+			}
+			*deleted++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+// gammago/gammasync/sync_test.go
+
+package gammasync
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// fakeFetcher serves fixed slices and counts page requests
+type fakeFetcher struct {
+	events  []gamma.Event
+	markets []gamma.Market
+	tags    []gamma.Tag
+	series  []gamma.Series
+
+	eventPages int
+}
+
+func page[T any](all []T, limit, offset int) []T {
+	if offset >= len(all) {
+		return nil
+	}
+	return all[offset:min(offset+limit, len(all))]
+}
+
+func (f *fakeFetcher) EventsByUpdatedAt(limit, offset int) ([]gamma.Event, error) {
+	f.eventPages++
+	return page(f.events, limit, offset), nil
+}
+
+func (f *fakeFetcher) MarketsByUpdatedAt(limit, offset int) ([]gamma.Market, error) {
+	return page(f.markets, limit, offset), nil
+}
+
+func (f *fakeFetcher) Tags(limit, offset int) ([]gamma.Tag, error) {
+	return page(f.tags, limit, offset), nil
+}
+
+func (f *fakeFetcher) Series(limit, offset int) ([]gamma.Series, error) {
+	return page(f.series, limit, offset), nil
+}
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func openStore(t *testing.T, path string) *Store {
+	t.Helper()
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSync(t *testing.T) {
+	t.Run("first run stores everything and sets watermarks", func(t *testing.T) {
+		store := openStore(t, filepath.Join(t.TempDir(), "gamma.jsonl"))
+		f := &fakeFetcher{
+			events: []gamma.Event{
+				{ID: "3", Active: true, UpdatedAt: t0.Add(3 * time.Hour)},
+				{ID: "2", Active: true, UpdatedAt: t0.Add(2 * time.Hour)},
+				{ID: "1", Active: true, UpdatedAt: t0.Add(1 * time.Hour)},
+			},
+			markets: []gamma.Market{{ID: "10", Active: true, UpdatedAt: t0}},
+			tags:    []gamma.Tag{{ID: "7", Label: "Sports"}},
+			series:  []gamma.Series{{ID: "4", Title: "NBA"}},
+		}
+
+		res, err := (&Syncer{Store: store, Fetcher: f, PageSize: 2}).Run(t.Context())
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		want := Result{Events: 3, Markets: 1, Tags: 1, Series: 1}
+		if res != want {
+			t.Errorf("Expected %+v, got %+v", want, res)
+		}
+		if wm := store.Watermark(KindEvent); !wm.Equal(t0.Add(3 * time.Hour)) {
+			t.Errorf("Expected event watermark %v, got %v", t0.Add(3*time.Hour), wm)
+		}
+		if got := len(store.Events(nil)); got != 3 {
+			t.Errorf("Expected 3 events, got %d", got)
+		}
+		if tag := store.Tags(nil); len(tag) != 1 || tag[0].Label != "Sports" {
+			t.Errorf("Expected Sports tag, got %+v", tag)
+		}
+	})
+
+	t.Run("second run stops at the watermark", func(t *testing.T) {
+		store := openStore(t, filepath.Join(t.TempDir(), "gamma.jsonl"))
+		f := &fakeFetcher{events: []gamma.Event{
+			{ID: "2", Active: true, UpdatedAt: t0.Add(2 * time.Hour)},
+			{ID: "1", Active: true, UpdatedAt: t0.Add(1 * time.Hour)},
+		}}
+		syncer := &Syncer{Store: store, Fetcher: f, PageSize: 1}
+		if _, err := syncer.Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// one new event on top, the old ones are below the watermark
+		f.events = append([]gamma.Event{{ID: "5", Active: true, UpdatedAt: t0.Add(5 * time.Hour)}}, f.events...)
+		f.events[1].UpdatedAt = t0 // below the watermark, so never written
+		f.eventPages = 0
+
+		res, err := syncer.Run(t.Context())
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if res.Events != 1 {
+			t.Errorf("Expected 1 event written, got %d", res.Events)
+		}
+		// page 1 has the new event, page 2 is below the watermark
+		if f.eventPages != 2 {
+			t.Errorf("Expected 2 page fetches, got %d", f.eventPages)
+		}
+	})
+
+	t.Run("closures are recorded when closed flips on", func(t *testing.T) {
+		store := openStore(t, filepath.Join(t.TempDir(), "gamma.jsonl"))
+		b, err := os.ReadFile("../testdata/market.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var open gamma.Market
+		if err := json.Unmarshal(b, &open); err != nil {
+			t.Fatal(err)
+		}
+		if !open.Active || open.Closed {
+			t.Fatalf("fixture should be an active, open market")
+		}
+		f := &fakeFetcher{markets: []gamma.Market{open}}
+		syncer := &Syncer{Store: store, Fetcher: f}
+		if _, err := syncer.Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		// Gamma keeps active=true on closed markets; only closed changes
+		closed := open
+		closed.Closed = true
+		closed.UpdatedAt = open.UpdatedAt.Add(time.Hour)
+		f.markets = []gamma.Market{closed}
+		res, err := syncer.Run(t.Context())
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if res.Closed != 1 {
+			t.Errorf("Expected 1 closure, got %d", res.Closed)
+		}
+
+		changes := store.Changes(time.Time{})
+		if len(changes) != 1 || changes[0].ID != open.ID || changes[0].Op != OpClosed || changes[0].Kind != KindMarket {
+			t.Errorf("Expected market %s closed, got %+v", open.ID, changes)
+		}
+		if m, ok := store.Market(open.ID); !ok || !m.Closed || !m.Active {
+			t.Errorf("Expected stored market to be closed, got %+v", m)
+		}
+
+		// a later update to the closed market is not another closure
+		f.markets[0].UpdatedAt = closed.UpdatedAt.Add(time.Hour)
+		f.markets[0].Volume24hr = 5
+		if res, err := syncer.Run(t.Context()); err != nil || res.Closed != 0 {
+			t.Errorf("Expected no new closure, got %+v, %v", res, err)
+		}
+	})
+
+	t.Run("an inactive but open market is not closed", func(t *testing.T) {
+		store := openStore(t, filepath.Join(t.TempDir(), "gamma.jsonl"))
+		f := &fakeFetcher{events: []gamma.Event{{ID: "3", Active: true, UpdatedAt: t0}}}
+		syncer := &Syncer{Store: store, Fetcher: f}
+		if _, err := syncer.Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		f.events = []gamma.Event{{ID: "3", Active: false, UpdatedAt: t0.Add(time.Hour)}}
+		if res, err := syncer.Run(t.Context()); err != nil || res.Closed != 0 || res.Events != 1 {
+			t.Errorf("Expected the update without a closure, got %+v, %v", res, err)
+		}
+	})
+
+	t.Run("tags missing from a full pull are deleted", func(t *testing.T) {
+		store := openStore(t, filepath.Join(t.TempDir(), "gamma.jsonl"))
+		f := &fakeFetcher{tags: []gamma.Tag{{ID: "1"}, {ID: "2"}}}
+		syncer := &Syncer{Store: store, Fetcher: f}
+		if _, err := syncer.Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		f.tags = f.tags[:1]
+		res, err := syncer.Run(t.Context())
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if res.Deleted != 1 || res.Tags != 0 {
+			t.Errorf("Expected 1 deletion and no writes, got %+v", res)
+		}
+		if tags := store.Tags(nil); len(tags) != 1 || tags[0].ID != "1" {
+			t.Errorf("Expected only tag 1 left, got %+v", tags)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		store := openStore(t, filepath.Join(t.TempDir(), "gamma.jsonl"))
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		if _, err := (&Syncer{Store: store, Fetcher: &fakeFetcher{}}).Run(ctx); err == nil {
+			t.Error("Expected error for cancelled context")
+		}
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("reopen replays the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "gamma.jsonl")
+		s, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		f := &fakeFetcher{
+			events: []gamma.Event{{ID: "1", Title: "First", Active: true, UpdatedAt: t0}},
+			tags:   []gamma.Tag{{ID: "1"}, {ID: "2"}},
+		}
+		if _, err := (&Syncer{Store: s, Fetcher: f}).Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		f.tags = nil
+		if _, err := (&Syncer{Store: s, Fetcher: f}).Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		s.Close()
+
+		s = openStore(t, path)
+		if e, ok := s.Event("1"); !ok || e.Title != "First" {
+			t.Errorf("Expected event 1 after reopen, got %+v", e)
+		}
+		if !s.Watermark(KindEvent).Equal(t0) {
+			t.Errorf("Expected watermark %v, got %v", t0, s.Watermark(KindEvent))
+		}
+		if len(s.Tags(nil)) != 0 || len(s.Changes(time.Time{})) != 2 {
+			t.Errorf("Expected tag deletions to survive reopen, got tags %v changes %v", s.Tags(nil), s.Changes(time.Time{}))
+		}
+	})
+
+	t.Run("torn final line is dropped", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "gamma.jsonl")
+		s, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		f := &fakeFetcher{tags: []gamma.Tag{{ID: "1"}}}
+		if _, err := (&Syncer{Store: s, Fetcher: f}).Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		s.Close()
+
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.WriteString(`{"kind":"tag","id":"2","op":"pu`)
+		file.Close()
+
+		s = openStore(t, path)
+		if tags := s.Tags(nil); len(tags) != 1 {
+			t.Errorf("Expected 1 tag, got %+v", tags)
+		}
+		data, _ := os.ReadFile(path)
+		if data[len(data)-1] != '\n' {
+			t.Error("Expected torn line to be truncated")
+		}
+	})
+
+	t.Run("corrupt complete line is an error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "gamma.jsonl")
+		os.WriteFile(path, []byte("not json\n"), 0o644)
+		if _, err := Open(path); err == nil {
+			t.Error("Expected error for corrupt record")
+		}
+	})
+
+	t.Run("compact keeps state and shrinks the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "gamma.jsonl")
+		s := openStore(t, path)
+		f := &fakeFetcher{}
+		syncer := &Syncer{Store: s, Fetcher: f}
+		for i := range 5 {
+			f.markets = []gamma.Market{{ID: "10", Volume24hr: float64(i), Active: true, UpdatedAt: t0.Add(time.Duration(i) * time.Hour)}}
+			if _, err := syncer.Run(t.Context()); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+		}
+		before, _ := os.Stat(path)
+
+		if err := s.Compact(); err != nil {
+			t.Fatalf("Compact failed: %v", err)
+		}
+		after, _ := os.Stat(path)
+		if after.Size() >= before.Size() {
+			t.Errorf("Expected file to shrink, got %d -> %d bytes", before.Size(), after.Size())
+		}
+
+		// the store keeps writing to the compacted file
+		f.markets[0].Closed = true
+		f.markets[0].UpdatedAt = t0.Add(10 * time.Hour)
+		if _, err := syncer.Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		s.Close()
+
+		s = openStore(t, path)
+		m, ok := s.Market("10")
+		if !ok || m.Volume24hr != 4 || !m.Closed {
+			t.Errorf("Expected latest market after compact and reopen, got %+v", m)
+		}
+		if len(s.Changes(time.Time{})) != 1 {
+			t.Errorf("Expected 1 closure, got %+v", s.Changes(time.Time{}))
+		}
+	})
+}
+
+func TestCompactKeepsRecreatedRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gamma.jsonl")
+	s := openStore(t, path)
+	f := &fakeFetcher{tags: []gamma.Tag{{ID: "7", Label: "Sports"}}}
+	syncer := &Syncer{Store: s, Fetcher: f}
+
+	// sync tag 7, delete it, add it back
+	for _, tags := range [][]gamma.Tag{f.tags, nil, f.tags} {
+		f.tags = tags
+		if _, err := syncer.Run(t.Context()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+	if got := len(s.Tags(nil)); got != 1 {
+		t.Fatalf("Expected 1 tag before compacting, got %d", got)
+	}
+
+	if err := s.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	s.Close()
+
+	s = openStore(t, path)
+	if tags := s.Tags(nil); len(tags) != 1 || tags[0].Label != "Sports" {
+		t.Errorf("Expected tag 7 after compact and reopen, got %+v", tags)
+	}
+	if changes := s.Changes(time.Time{}); len(changes) != 1 || changes[0].Op != OpDeleted {
+		t.Errorf("Expected the deletion to stay in the history, got %+v", changes)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	store := openStore(t, filepath.Join(t.TempDir(), "gamma.jsonl"))
+	f := &fakeFetcher{markets: []gamma.Market{
+		{ID: "100", Active: true, UpdatedAt: t0.Add(2 * time.Hour)},
+		{ID: "9", Active: false, UpdatedAt: t0.Add(time.Hour)},
+		{ID: "20", Active: true, UpdatedAt: t0},
+	}}
+	if _, err := (&Syncer{Store: store, Fetcher: f}).Run(t.Context()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	all := store.Markets(nil)
+	var ids []string
+	for _, m := range all {
+		ids = append(ids, m.ID)
+	}
+	if len(ids) != 3 || ids[0] != "9" || ids[1] != "20" || ids[2] != "100" {
+		t.Errorf("Expected numeric ID order [9 20 100], got %v", ids)
+	}
+
+	active := store.Markets(func(m gamma.Market) bool { return m.Active })
+	if len(active) != 2 {
+		t.Errorf("Expected 2 active markets, got %d", len(active))
+	}
+
+	if _, ok := store.Market("404"); ok {
+		t.Error("Expected missing market to not be found")
+	}
+}
@@ -0,0 +1,70 @@
+// gammago/history/bars.go
+
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// Bar is the price of one outcome over one interval
+// Volume24hr and Liquidity are the market's values at the last sample in
+// the interval, since Gamma reports them as rolling figures
+type Bar struct {
+	Start      time.Time
+	Open       float64
+	High       float64
+	Low        float64
+	Close      float64
+	Volume24hr float64
+	Liquidity  float64
+	Samples    int
+}
+
+// OutcomeBars is the bar series for one outcome
+// Intervals without samples are left out rather than filled
+type OutcomeBars struct {
+	Outcome string
+	Index   int
+	Bars    []Bar
+}
+
+// Bars aggregates samples of a single market into bars of the given
+// interval, one series per outcome. Intervals are aligned to the Unix epoch,
+// so hourly bars start on the hour in UTC. Samples needn't be sorted
+func Bars(samples []Sample, interval time.Duration) []OutcomeBars {
+	if len(samples) == 0 || interval <= 0 {
+		return nil
+	}
+	sorted := make([]Sample, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var series []OutcomeBars
+	for _, s := range sorted {
+		start := s.Time.Truncate(interval).UTC()
+		for i, p := range s.Prices {
+			for len(series) <= i {
+				series = append(series, OutcomeBars{Index: len(series)})
+			}
+			ob := &series[i]
+			if i < len(s.Outcomes) {
+				ob.Outcome = s.Outcomes[i]
+			}
+
+			n := len(ob.Bars)
+			if n == 0 || !ob.Bars[n-1].Start.Equal(start) {
+				ob.Bars = append(ob.Bars, Bar{Start: start, Open: p, High: p, Low: p})
+				n++
+			}
+			b := &ob.Bars[n-1]
+			b.High = max(b.High, p)
+			b.Low = min(b.Low, p)
+			b.Close = p
+			b.Volume24hr = s.Volume24hr
+			b.Liquidity = s.Liquidity
+			b.Samples++
+		}
+	}
+	return series
+}
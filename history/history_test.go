@@ -0,0 +1,190 @@
+// gammago/history/history_test.go
+
+package history
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func sample(min int, yes float64, volume float64) Sample {
+	return Sample{
+		Time:       t0.Add(time.Duration(min) * time.Minute),
+		MarketID:   "501",
+		Outcomes:   []string{"Yes", "No"},
+		Prices:     []float64{yes, 1 - yes},
+		Volume24hr: volume,
+		Liquidity:  100,
+	}
+}
+
+func TestBars(t *testing.T) {
+	samples := []Sample{
+		sample(0, 0.50, 10),
+		sample(20, 0.58, 11),
+		sample(10, 0.45, 12), // out of order on purpose
+		sample(50, 0.52, 13),
+		sample(65, 0.60, 14),
+		sample(190, 0.70, 15), // the 14:00 hour is empty
+	}
+
+	series := Bars(samples, time.Hour)
+	if len(series) != 2 || series[0].Outcome != "Yes" || series[1].Outcome != "No" || series[1].Index != 1 {
+		t.Fatalf("Expected Yes and No series, got %+v", series)
+	}
+
+	yes := series[0].Bars
+	if len(yes) != 3 {
+		t.Fatalf("Expected 3 hourly bars, got %d", len(yes))
+	}
+	want := Bar{Start: t0, Open: 0.50, High: 0.58, Low: 0.45, Close: 0.52, Volume24hr: 13, Liquidity: 100, Samples: 4}
+	if yes[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, yes[0])
+	}
+	if !yes[1].Start.Equal(t0.Add(time.Hour)) || yes[1].Samples != 1 || yes[1].Open != 0.60 {
+		t.Errorf("Unexpected second bar %+v", yes[1])
+	}
+	if !yes[2].Start.Equal(t0.Add(3*time.Hour)) || yes[2].Close != 0.70 {
+		t.Errorf("Unexpected third bar %+v", yes[2])
+	}
+
+	no := series[1].Bars[0]
+	if math.Abs(no.High-0.55) > 1e-9 || math.Abs(no.Low-0.42) > 1e-9 {
+		t.Errorf("Expected No high 0.55 low 0.42, got %+v", no)
+	}
+
+	if Bars(nil, time.Hour) != nil || Bars(samples, 0) != nil {
+		t.Error("Expected no bars for no samples or a zero interval")
+	}
+}
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+	l, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog failed: %v", err)
+	}
+	other := sample(5, 0.9, 0)
+	other.MarketID = "502"
+	if err := l.Append(sample(0, 0.5, 10), other, sample(30, 0.6, 11), sample(90, 0.7, 12)); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	t.Run("samples filter by market and time", func(t *testing.T) {
+		got, err := l.Samples("501", t0, t0.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("Samples failed: %v", err)
+		}
+		if len(got) != 2 || got[1].Prices[0] != 0.6 || got[1].Outcomes[0] != "Yes" || !got[1].Time.Equal(t0.Add(30*time.Minute)) {
+			t.Errorf("Unexpected samples %+v", got)
+		}
+
+		all, _ := l.Samples("", time.Time{}, time.Time{})
+		if len(all) != 4 {
+			t.Errorf("Expected 4 samples in total, got %d", len(all))
+		}
+	})
+
+	t.Run("bars", func(t *testing.T) {
+		series, err := l.Bars("501", time.Hour, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatalf("Bars failed: %v", err)
+		}
+		if len(series) != 2 || len(series[0].Bars) != 2 || series[0].Bars[0].Close != 0.6 {
+			t.Errorf("Unexpected bars %+v", series)
+		}
+	})
+
+	t.Run("torn line is dropped on reopen", func(t *testing.T) {
+		if err := l.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		f.WriteString(`{"t":17,"m":"5`)
+		f.Close()
+
+		if got, err := ReadSamples(path, "", time.Time{}, time.Time{}); err != nil || len(got) != 4 {
+			t.Errorf("Expected reader to skip the torn line, got %d samples and %v", len(got), err)
+		}
+
+		l, err = OpenLog(path)
+		if err != nil {
+			t.Fatalf("OpenLog failed: %v", err)
+		}
+		defer l.Close()
+		l.Append(sample(120, 0.8, 13))
+		got, err := l.Samples("501", time.Time{}, time.Time{})
+		if err != nil || len(got) != 4 || got[3].Prices[0] != 0.8 {
+			t.Errorf("Expected appends after the last good line, got %+v %v", got, err)
+		}
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+	l, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog failed: %v", err)
+	}
+	defer l.Close()
+
+	polls := 0
+	r := &Recorder{
+		Log: l,
+		Query: func(ctx context.Context) ([]gamma.Market, error) {
+			polls++
+			if polls == 2 {
+				return nil, errors.New("boom")
+			}
+			return []gamma.Market{
+				{ID: "501", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.4", "0.6"]`, Volume24hr: 50, Liquidity: "1234.5"},
+				{ID: "502"}, // no prices, skipped
+			}, nil
+		},
+		Interval: time.Millisecond,
+	}
+	minute := 0
+	r.now = func() time.Time {
+		minute++
+		return t0.Add(time.Duration(minute) * time.Minute)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var errs []error
+	r.OnError = func(err error) { errs = append(errs, err) }
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+	for {
+		got, _ := ReadSamples(path, "501", time.Time{}, time.Time{})
+		if len(got) >= 3 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	got, _ := ReadSamples(path, "", time.Time{}, time.Time{})
+	if got[0].MarketID != "501" || got[0].Liquidity != 1234.5 || got[0].Volume24hr != 50 || got[0].Outcomes[1] != "No" {
+		t.Errorf("Unexpected sample %+v", got[0])
+	}
+	for _, s := range got {
+		if s.MarketID != "501" {
+			t.Errorf("Expected market without prices to be skipped, got %+v", s)
+		}
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected the failed poll to be reported once, got %v", errs)
+	}
+}
@@ -0,0 +1,194 @@
+// gammago/history/log.go
+
+// Package history records market prices over time and rebuilds OHLC bars
+//
+// Gamma only serves current prices, so a Recorder polls the markets you
+// care about and appends each snapshot to a Log. Bars then aggregates the
+// samples into open/high/low/close per outcome for any interval
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sample is one market snapshot
+type Sample struct {
+	Time       time.Time
+	MarketID   string
+	Outcomes   []string
+	Prices     []float64
+	Volume24hr float64
+	Liquidity  float64
+}
+
+// line is the on-disk form of a Sample, one JSON object per line
+// Times are Unix milliseconds to keep lines short
+type line struct {
+	T int64     `json:"t"`
+	M string    `json:"m"`
+	O []string  `json:"o,omitempty"`
+	P []float64 `json:"p"`
+	V float64   `json:"v"`
+	L float64   `json:"l"`
+}
+
+// Log is an append-only file of samples in time order
+// A Log is safe for concurrent use
+type Log struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	w    *bufio.Writer
+}
+
+// OpenLog opens or creates the log at path for appending
+// A torn final line left by a crash is discarded
+func OpenLog(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	good, err := lastNewline(f)
+	if err == nil {
+		err = f.Truncate(good)
+	}
+	if err == nil {
+		_, err = f.Seek(good, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Log{path: path, f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// lastNewline is the offset just past the final complete line
+func lastNewline(r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+	var offset, good int64
+	for {
+		b, err := br.ReadBytes('\n')
+		offset += int64(len(b))
+		if len(b) > 0 && b[len(b)-1] == '\n' {
+			good = offset
+		}
+		if errors.Is(err, io.EOF) {
+			return good, nil
+		}
+		if err != nil {
+			return good, err
+		}
+	}
+}
+
+// Append writes samples to the log
+// They are buffered until Flush or Close
+func (l *Log) Append(samples ...Sample) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, s := range samples {
+		b, err := json.Marshal(line{
+			T: s.Time.UnixMilli(),
+			M: s.MarketID,
+			O: s.Outcomes,
+			P: s.Prices,
+			V: s.Volume24hr,
+			L: s.Liquidity,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := l.w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes buffered samples and syncs the file
+func (l *Log) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.w.Flush(); err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+// Close flushes and closes the log
+func (l *Log) Close() error {
+	if err := l.Flush(); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
+
+// Samples returns the samples for marketID in [from, to), oldest first
+// A zero from or to leaves that end open. Buffered samples are flushed first
+func (l *Log) Samples(marketID string, from, to time.Time) ([]Sample, error) {
+	if err := l.Flush(); err != nil {
+		return nil, err
+	}
+	return ReadSamples(l.path, marketID, from, to)
+}
+
+// Bars is Samples followed by Bars
+func (l *Log) Bars(marketID string, interval time.Duration, from, to time.Time) ([]OutcomeBars, error) {
+	samples, err := l.Samples(marketID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return Bars(samples, interval), nil
+}
+
+// ReadSamples reads the samples for marketID in [from, to) from the log
+// file at path without opening it for writing. An empty marketID matches
+// every market. A torn final line is ignored
+func ReadSamples(path, marketID string, from, to time.Time) ([]Sample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []Sample
+	br := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		b, err := br.ReadBytes('\n')
+		if len(b) > 0 && b[len(b)-1] == '\n' {
+			var ln line
+			if jsonErr := json.Unmarshal(b, &ln); jsonErr != nil {
+				return nil, fmt.Errorf("history: %s line %d: %w", path, n, jsonErr)
+			}
+			t := time.UnixMilli(ln.T).UTC()
+			if (marketID == "" || ln.M == marketID) &&
+				(from.IsZero() || !t.Before(from)) &&
+				(to.IsZero() || t.Before(to)) {
+				samples = append(samples, Sample{
+					Time:       t,
+					MarketID:   ln.M,
+					Outcomes:   ln.O,
+					Prices:     ln.P,
+					Volume24hr: ln.V,
+					Liquidity:  ln.L,
+				})
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
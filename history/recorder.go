@@ -0,0 +1,106 @@
+// gammago/history/recorder.go
+
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+	"github.com/Bazcampbell/gammago/watch"
+)
+
+// DefaultInterval is used when Recorder.Interval is 0
+const DefaultInterval = time.Minute
+
+// Recorder snapshots the markets returned by Query every Interval into Log
+type Recorder struct {
+	Log      *Log
+	Query    watch.Query[gamma.Market]
+	Interval time.Duration
+
+	// OnError is called when a poll or write fails; recording carries on
+	OnError func(error)
+
+	now func() time.Time
+}
+
+// Run records until ctx is cancelled, flushing after every poll
+// It returns ctx's error
+func (r *Recorder) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Record(ctx); err != nil && ctx.Err() == nil && r.OnError != nil {
+			r.OnError(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Record takes a single snapshot
+// Markets without readable prices are skipped
+func (r *Recorder) Record(ctx context.Context) error {
+	markets, err := r.Query(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	at := now().UTC()
+
+	samples := make([]Sample, 0, len(markets))
+	for _, m := range markets {
+		s, ok := sampleFromMarket(m, at)
+		if ok {
+			samples = append(samples, s)
+		}
+	}
+	if err := r.Log.Append(samples...); err != nil {
+		return err
+	}
+	return r.Log.Flush()
+}
+
+func sampleFromMarket(m gamma.Market, at time.Time) (Sample, bool) {
+	var raw []string
+	if json.Unmarshal([]byte(m.OutcomePrices), &raw) != nil || len(raw) == 0 {
+		return Sample{}, false
+	}
+	prices := make([]float64, len(raw))
+	for i, s := range raw {
+		p, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Sample{}, false
+		}
+		prices[i] = p
+	}
+
+	var outcomes []string
+	json.Unmarshal([]byte(m.Outcomes), &outcomes)
+
+	liquidity, _ := strconv.ParseFloat(m.Liquidity, 64)
+	return Sample{
+		Time:       at,
+		MarketID:   m.ID,
+		Outcomes:   outcomes,
+		Prices:     prices,
+		Volume24hr: m.Volume24hr,
+		Liquidity:  liquidity,
+	}, true
+}
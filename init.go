@@ -10,13 +10,17 @@ import (
 
 var (
 	once       sync.Once
+	clientMu   sync.Mutex // guards httpClient, which is created lazily
 	httpClient *http.Client
+	baseURL    = BASE_URL
 )
 
 // Initialise http client with timeout
 // Singleton pattern
 func InitCustomHttpClient(timeout int, transport *http.Transport) {
 	once.Do(func() {
+		clientMu.Lock()
+		defer clientMu.Unlock()
 		httpClient = &http.Client{
 			Timeout:   time.Duration(timeout),
 			Transport: transport,
@@ -25,7 +29,10 @@ func InitCustomHttpClient(timeout int, transport *http.Transport) {
 }
 
 // Or use a default if not initialized
+// Safe for concurrent callers; the default is created once
 func getHTTPClient() *http.Client {
+	clientMu.Lock()
+	defer clientMu.Unlock()
 	if httpClient == nil {
 		httpClient = &http.Client{
 			Timeout: 30 * time.Second,
@@ -33,3 +40,20 @@ func getHTTPClient() *http.Client {
 	}
 	return httpClient
 }
+
+// SetBaseURL points requests at another Gamma-compatible host, such as a
+// caching proxy or a test server. Pass "" to go back to BASE_URL
+func SetBaseURL(u string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if u == "" {
+		u = BASE_URL
+	}
+	baseURL = u
+}
+
+func getBaseURL() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return baseURL
+}
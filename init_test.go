@@ -66,3 +66,25 @@ func TestInitHTTPClient(t *testing.T) {
 		}
 	})
 }
+
+func TestGetHTTPClientConcurrent(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	clients := make([]*http.Client, 16)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients[i] = getDoer().(*http.Client)
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range clients {
+		if c != clients[0] {
+			t.Fatal("concurrent callers got different default clients")
+		}
+	}
+}
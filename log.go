@@ -0,0 +1,118 @@
+// gammago/log.go
+
+package gammago
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+var (
+	logger         *slog.Logger
+	redactedParams map[string]bool
+)
+
+// SetLogger sets the logger used for request records
+// Each attempt is logged at debug, exhausted retries at warn
+// Pass nil to disable logging (the default)
+func SetLogger(l *slog.Logger) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	logger = l
+}
+
+// SetRedactedParams replaces query parameter values with REDACTED in log records
+// Use "*" to redact every value
+func SetRedactedParams(keys ...string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	redactedParams = make(map[string]bool, len(keys))
+	for _, k := range keys {
+		redactedParams[k] = true
+	}
+}
+
+func getLogger() *slog.Logger {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return logger
+}
+
+// redactQuery returns the raw query of rawUrl with redacted values replaced
+func redactQuery(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	if len(redactedParams) == 0 {
+		return u.RawQuery
+	}
+
+	q := u.Query()
+	for k, vs := range q {
+		if redactedParams["*"] || redactedParams[k] {
+			for i := range vs {
+				vs[i] = redacted
+			}
+		}
+	}
+	return q.Encode()
+}
+
+func logAttempt(l *slog.Logger, info RequestInfo, status, bytes int, err error) {
+	if l == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("endpoint", info.Endpoint),
+		slog.String("query", redactQuery(info.URL)),
+		slog.Int("attempt", info.Attempt),
+		slog.Int("status", status),
+		slog.Int("bytes", bytes),
+		slog.Duration("latency", time.Since(info.Start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.LogAttrs(context.Background(), slog.LevelDebug, "gamma request", attrs...)
+}
+
+func logRetry(l *slog.Logger, info RetryInfo) {
+	if l == nil {
+		return
+	}
+
+	reason := ""
+	if info.Reason != nil {
+		reason = info.Reason.Error()
+	}
+	l.LogAttrs(context.Background(), slog.LevelDebug, "gamma retry",
+		slog.String("endpoint", info.Endpoint),
+		slog.String("query", redactQuery(info.URL)),
+		slog.Int("attempt", info.Attempt),
+		slog.Duration("delay", info.Delay),
+		slog.String("reason", reason),
+	)
+}
+
+func logExhausted(l *slog.Logger, endpoint, rawUrl string, attempts int, err error) {
+	if l == nil {
+		return
+	}
+
+	l.LogAttrs(context.Background(), slog.LevelWarn, "gamma request failed",
+		slog.String("endpoint", endpoint),
+		slog.String("query", redactQuery(rawUrl)),
+		slog.Int("attempts", attempts),
+		slog.String("error", err.Error()),
+	)
+}
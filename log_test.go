@@ -0,0 +1,85 @@
+// gammago/log_test.go
+
+package gammago
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func resetLogger() {
+	configMu.Lock()
+	defer configMu.Unlock()
+	logger = nil
+	redactedParams = nil
+}
+
+func TestLogging(t *testing.T) {
+	t.Run("redactQuery", func(t *testing.T) {
+		defer resetLogger()
+
+		tests := []struct {
+			name string
+			keys []string
+			url  string
+			want string
+		}{
+			{"no redaction", nil, "https://x/events?tag_id=1&limit=5", "tag_id=1&limit=5"},
+			{"single key", []string{"tag_id"}, "https://x/events?tag_id=1&limit=5", "limit=5&tag_id=" + redacted},
+			{"wildcard", []string{"*"}, "https://x/events?tag_id=1&limit=5", "limit=" + redacted + "&tag_id=" + redacted},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				SetRedactedParams(tt.keys...)
+				if got := redactQuery(tt.url); got != tt.want {
+					t.Errorf("redactQuery(%q) = %q, want %q", tt.url, got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("emits debug per attempt and warn when retries are exhausted", func(t *testing.T) {
+		resetHTTPClient()
+		resetMiddleware()
+		defer resetLogger()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		var buf bytes.Buffer
+		SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		SetRedactedParams("tag_id")
+
+		_, err := genericGet[[]Event]("/events", srv.URL+"/events?tag_id=42")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %d log records, want 2:\n%s", len(lines), buf.String())
+		}
+
+		var attempt, exhausted map[string]any
+		json.Unmarshal([]byte(lines[0]), &attempt)
+		json.Unmarshal([]byte(lines[1]), &exhausted)
+
+		if attempt["level"] != "DEBUG" || attempt["endpoint"] != "/events" || attempt["status"] != float64(404) {
+			t.Errorf("unexpected attempt record: %v", attempt)
+		}
+		if attempt["query"] != "tag_id="+redacted {
+			t.Errorf("query not redacted: %v", attempt["query"])
+		}
+		if exhausted["level"] != "WARN" {
+			t.Errorf("final record level = %v, want WARN", exhausted["level"])
+		}
+	})
+}
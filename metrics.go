@@ -0,0 +1,228 @@
+// gammago/metrics.go
+
+package gammago
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Latency histogram buckets in seconds (Prometheus defaults)
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics collects request counters and latency histograms
+// Render them in Prometheus text exposition format with Handler or WriteTo
+// A Metrics is safe for concurrent use
+type Metrics struct {
+	mu             sync.Mutex
+	requests       map[requestKey]uint64
+	latency        map[string]*histogram
+	retries        map[string]uint64
+	retryWait      map[string]float64
+	throttled      map[string]uint64
+	decodeFailures map[string]uint64
+}
+
+type requestKey struct {
+	endpoint string
+	code     string
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, non-cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics returns an empty collector
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:       make(map[requestKey]uint64),
+		latency:        make(map[string]*histogram),
+		retries:        make(map[string]uint64),
+		retryWait:      make(map[string]float64),
+		throttled:      make(map[string]uint64),
+		decodeFailures: make(map[string]uint64),
+	}
+}
+
+var metrics *Metrics
+
+// SetMetrics sets the collector every request reports to
+// Pass nil to disable collection (the default)
+func SetMetrics(m *Metrics) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	metrics = m
+}
+
+func getMetrics() *Metrics {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return metrics
+}
+
+// observeResponse records one attempt
+// status 0 means the transport failed and is recorded as code="error"
+func (m *Metrics) observeResponse(endpoint string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{endpoint, code}]++
+	if status == http.StatusTooManyRequests {
+		m.throttled[endpoint]++
+	}
+
+	h, ok := m.latency[endpoint]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.latency[endpoint] = h
+	}
+	secs := d.Seconds()
+	for i, b := range latencyBuckets {
+		if secs <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += secs
+}
+
+func (m *Metrics) observeRetry(endpoint string, delay time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[endpoint]++
+	m.retryWait[endpoint] += delay.Seconds()
+}
+
+func (m *Metrics) observeDecodeFailure(endpoint string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.decodeFailures[endpoint]++
+}
+
+// Handler serves the collected metrics in Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
+
+// WriteTo writes the collected metrics in Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	writeHeader(bw, "gammago_requests_total", "counter", "Requests sent, by endpoint and status code.")
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].code < keys[j].code
+	})
+	for _, k := range keys {
+		fmt.Fprintf(bw, "gammago_requests_total{endpoint=\"%s\",code=\"%s\"} %d\n",
+			escapeLabel(k.endpoint), k.code, m.requests[k])
+	}
+
+	writeHeader(bw, "gammago_request_duration_seconds", "histogram", "Request latency per attempt.")
+	for _, endpoint := range sortedKeys(m.latency) {
+		h := m.latency[endpoint]
+		label := escapeLabel(endpoint)
+		var cumulative uint64
+		for i, b := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(bw, "gammago_request_duration_seconds_bucket{endpoint=\"%s\",le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(b, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(bw, "gammago_request_duration_seconds_bucket{endpoint=\"%s\",le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(bw, "gammago_request_duration_seconds_sum{endpoint=\"%s\"} %s\n", label, formatFloat(h.sum))
+		fmt.Fprintf(bw, "gammago_request_duration_seconds_count{endpoint=\"%s\"} %d\n", label, h.count)
+	}
+
+	writeCounter(bw, "gammago_retries_total", "Retries after a failed attempt.", m.retries)
+	writeHeader(bw, "gammago_retry_wait_seconds_total", "counter", "Time spent backing off before retries.")
+	for _, endpoint := range sortedKeys(m.retryWait) {
+		fmt.Fprintf(bw, "gammago_retry_wait_seconds_total{endpoint=\"%s\"} %s\n",
+			escapeLabel(endpoint), formatFloat(m.retryWait[endpoint]))
+	}
+	writeCounter(bw, "gammago_throttled_responses_total", "Responses with status 429 Too Many Requests.", m.throttled)
+	writeCounter(bw, "gammago_decode_failures_total", "2xx responses that failed to unmarshal.", m.decodeFailures)
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeCounter(w io.Writer, name, help string, values map[string]uint64) {
+	writeHeader(w, name, "counter", help)
+	for _, endpoint := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s{endpoint=\"%s\"} %d\n", name, escapeLabel(endpoint), values[endpoint])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
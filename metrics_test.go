@@ -0,0 +1,106 @@
+// gammago/metrics_test.go
+
+package gammago
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("records requests, latency and decode failures", func(t *testing.T) {
+		resetHTTPClient()
+		resetMiddleware()
+		defer SetMetrics(nil)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/sports":
+				w.Write([]byte(`[]`))
+			case "/teams":
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer srv.Close()
+
+		m := NewMetrics()
+		SetMetrics(m)
+
+		if _, err := genericGet[[]Sport]("/sports", srv.URL+"/sports"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := genericGet[[]Team]("/teams", srv.URL+"/teams"); err == nil {
+			t.Fatal("expected error for 400, got nil")
+		}
+
+		var sb strings.Builder
+		if _, err := m.WriteTo(&sb); err != nil {
+			t.Fatalf("WriteTo failed: %v", err)
+		}
+		out := sb.String()
+
+		for _, want := range []string{
+			`gammago_requests_total{endpoint="/sports",code="200"} 1`,
+			`gammago_requests_total{endpoint="/teams",code="400"} 1`,
+			`gammago_request_duration_seconds_bucket{endpoint="/sports",le="+Inf"} 1`,
+			`gammago_request_duration_seconds_count{endpoint="/teams"} 1`,
+			"# TYPE gammago_decode_failures_total counter",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("histogram buckets are cumulative", func(t *testing.T) {
+		m := NewMetrics()
+		m.observeResponse("/events", 200, 3*time.Millisecond)
+		m.observeResponse("/events", 200, 200*time.Millisecond)
+		m.observeResponse("/events", 429, 20*time.Second)
+		m.observeRetry("/events", 2*time.Second)
+
+		var sb strings.Builder
+		m.WriteTo(&sb)
+		out := sb.String()
+
+		for _, want := range []string{
+			`gammago_request_duration_seconds_bucket{endpoint="/events",le="0.005"} 1`,
+			`gammago_request_duration_seconds_bucket{endpoint="/events",le="0.25"} 2`,
+			`gammago_request_duration_seconds_bucket{endpoint="/events",le="10"} 2`,
+			`gammago_request_duration_seconds_bucket{endpoint="/events",le="+Inf"} 3`,
+			`gammago_throttled_responses_total{endpoint="/events"} 1`,
+			`gammago_retries_total{endpoint="/events"} 1`,
+			`gammago_retry_wait_seconds_total{endpoint="/events"} 2`,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("handler serves text exposition format", func(t *testing.T) {
+		m := NewMetrics()
+		m.observeDecodeFailure(`/tags/"odd"`)
+
+		srv := httptest.NewServer(m.Handler())
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("content type = %q", ct)
+		}
+		if want := `gammago_decode_failures_total{endpoint="/tags/\"odd\""} 1`; !strings.Contains(string(body), want) {
+			t.Errorf("body missing %q\n%s", want, body)
+		}
+	})
+}
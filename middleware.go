@@ -0,0 +1,108 @@
+// gammago/middleware.go
+
+package gammago
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Doer sends an HTTP request and returns its response
+// *http.Client satisfies Doer
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a plain function to the Doer interface
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Doer to observe or modify requests and responses
+type Middleware func(next Doer) Doer
+
+// RequestInfo describes a single attempt at calling an endpoint
+// Endpoint is the route template (e.g. "/events/{id}"), not the full URL
+type RequestInfo struct {
+	Endpoint string
+	URL      string
+	Attempt  int
+	Start    time.Time
+}
+
+// ResponseInfo is passed to OnResponse after every attempt
+// Err is set when the transport failed or the body could not be read
+type ResponseInfo struct {
+	RequestInfo
+	StatusCode int
+	Bytes      int
+	Duration   time.Duration
+	Err        error
+}
+
+// RetryInfo is passed to OnRetry before sleeping ahead of the next attempt
+// Attempt is the attempt about to be made
+type RetryInfo struct {
+	RequestInfo
+	Delay  time.Duration
+	Reason error
+}
+
+// DecodeErrorInfo is passed to OnDecodeError when a 2xx body fails to unmarshal
+type DecodeErrorInfo struct {
+	RequestInfo
+	Body []byte
+	Err  error
+}
+
+// Hooks are optional callbacks fired during the request lifecycle
+// Nil hooks are skipped. Hooks run synchronously on the calling goroutine
+type Hooks struct {
+	OnRequest     func(RequestInfo)
+	OnResponse    func(ResponseInfo)
+	OnRetry       func(RetryInfo)
+	OnDecodeError func(DecodeErrorInfo)
+}
+
+var (
+	configMu    sync.RWMutex
+	middlewares []Middleware
+	hooks       Hooks
+)
+
+// Use appends middleware to the chain wrapping every request
+// The first registered middleware is the outermost
+func Use(mw ...Middleware) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	middlewares = append(middlewares, mw...)
+}
+
+// SetHooks replaces the lifecycle hooks
+func SetHooks(h Hooks) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	hooks = h
+}
+
+// getDoer wraps the http client in the registered middleware
+func getDoer() Doer {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	var d Doer = getHTTPClient()
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		d = middlewares[i](d)
+	}
+	return d
+}
+
+func getHooks() Hooks {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return hooks
+}
@@ -0,0 +1,121 @@
+// gammago/middleware_test.go
+
+package gammago
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func resetMiddleware() {
+	configMu.Lock()
+	defer configMu.Unlock()
+	middlewares = nil
+	hooks = Hooks{}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("middleware runs in registration order and can set headers", func(t *testing.T) {
+		resetHTTPClient()
+		resetMiddleware()
+		defer resetMiddleware()
+
+		var gotHeader string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotHeader = r.Header.Get("X-Proxy-Auth")
+			w.Write([]byte(`[{"id":"1","label":"Sports","slug":"sports"}]`))
+		}))
+		defer srv.Close()
+
+		var order []string
+		named := func(name string) Middleware {
+			return func(next Doer) Doer {
+				return DoerFunc(func(req *http.Request) (*http.Response, error) {
+					order = append(order, name)
+					return next.Do(req)
+				})
+			}
+		}
+		auth := func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Proxy-Auth", "secret")
+				return next.Do(req)
+			})
+		}
+
+		Use(named("outer"), named("inner"), auth)
+
+		tags, err := genericGet[[]Tag]("/tags", srv.URL+"/tags")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tags) != 1 || tags[0].Slug != "sports" {
+			t.Errorf("unexpected tags: %+v", tags)
+		}
+		if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+			t.Errorf("middleware order = %v, want [outer inner]", order)
+		}
+		if gotHeader != "secret" {
+			t.Errorf("header = %q, want %q", gotHeader, "secret")
+		}
+	})
+
+	t.Run("hooks receive endpoint, attempt and status", func(t *testing.T) {
+		resetHTTPClient()
+		resetMiddleware()
+		defer resetMiddleware()
+
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"id":"1"`))
+		}))
+		defer srv.Close()
+
+		var requests []RequestInfo
+		var responses []ResponseInfo
+		var retries []RetryInfo
+		var decodeErrs []DecodeErrorInfo
+
+		SetHooks(Hooks{
+			OnRequest:     func(i RequestInfo) { requests = append(requests, i) },
+			OnResponse:    func(i ResponseInfo) { responses = append(responses, i) },
+			OnRetry:       func(i RetryInfo) { retries = append(retries, i) },
+			OnDecodeError: func(i DecodeErrorInfo) { decodeErrs = append(decodeErrs, i) },
+		})
+
+		_, err := genericGet[Tag]("/tags/slug/{slug}", srv.URL+"/tags/slug/nfl")
+		if err == nil {
+			t.Fatal("expected decode error, got nil")
+		}
+
+		if len(requests) != maxRetries {
+			t.Fatalf("OnRequest calls = %d, want %d", len(requests), maxRetries)
+		}
+		for i, r := range requests {
+			if r.Endpoint != "/tags/slug/{slug}" {
+				t.Errorf("endpoint = %q, want route template", r.Endpoint)
+			}
+			if r.Attempt != i {
+				t.Errorf("attempt = %d, want %d", r.Attempt, i)
+			}
+		}
+		if responses[0].StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("first status = %d, want 503", responses[0].StatusCode)
+		}
+		if len(retries) != maxRetries-1 {
+			t.Errorf("OnRetry calls = %d, want %d", len(retries), maxRetries-1)
+		}
+		if retries[0].Reason == nil || retries[0].Delay <= 0 {
+			t.Errorf("retry info missing reason or delay: %+v", retries[0])
+		}
+		if len(decodeErrs) != maxRetries-1 {
+			t.Errorf("OnDecodeError calls = %d, want %d", len(decodeErrs), maxRetries-1)
+		}
+	})
+}
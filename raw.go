@@ -0,0 +1,122 @@
+// gammago/raw.go
+
+package gammago
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// GetRaw gets any Gamma path and returns the JSON body untouched
+// It goes through the same retries, middleware, hooks, logging, metrics and
+// tracing as the typed functions. Bodies that aren't valid JSON are retried
+// like a decode failure. A non-2xx status after retries is a *StatusError
+// Paths outside the known Gamma routes are all reported as the endpoint
+// "other", so passing client paths through can't grow the metric series
+// without bound
+func GetRaw(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	return getRaw(ctx, getBaseURL(), gammaRoute(path), path, params)
+}
+
+// GetRawFrom is GetRaw against another Polymarket host, such as the CLOB
+// SetBaseURL doesn't apply; base is used as given
+func GetRawFrom(ctx context.Context, base, path string, params url.Values) ([]byte, error) {
+	return getRaw(ctx, base, routeTemplate(path), path, params)
+}
+
+func getRaw(ctx context.Context, base, endpoint, path string, params url.Values) ([]byte, error) {
+	reqUrl, err := joinUrl(base, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	err = doGet(ctx, endpoint, reqUrl, func(r io.Reader) (int, error) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+		// arrays report their length as the result count
+		var list []json.RawMessage
+		if err := json.Unmarshal(b, &list); err == nil {
+			body = b
+			return len(list), nil
+		}
+		if !json.Valid(b) {
+			return 0, &decodeError{body: b, err: errors.New("invalid JSON")}
+		}
+		body = b
+		return 1, nil
+	})
+	return body, err
+}
+
+// otherRoute is the endpoint name GetRaw reports for unknown paths
+const otherRoute = "other"
+
+// gammaRoutes are the templated Gamma routes GetRaw reports by name
+var gammaRoutes = map[string]bool{
+	"/events":                             true,
+	"/events/{id}":                        true,
+	"/events/{id}/tags":                   true,
+	"/events/slug/{slug}":                 true,
+	"/markets":                            true,
+	"/markets/{id}":                       true,
+	"/markets/{id}/tags":                  true,
+	"/markets/slug/{slug}":                true,
+	"/series":                             true,
+	"/series/{id}":                        true,
+	"/tags":                               true,
+	"/tags/{id}":                          true,
+	"/tags/{id}/related-tags":             true,
+	"/tags/{id}/related-tags/tags":        true,
+	"/tags/slug/{slug}":                   true,
+	"/tags/slug/{slug}/related-tags":      true,
+	"/tags/slug/{slug}/related-tags/tags": true,
+	"/sports":                             true,
+	"/sports/market-types":                true,
+	"/teams":                              true,
+	"/comments":                           true,
+	"/comments/{id}":                      true,
+	"/public-profile":                     true,
+	"/public-search":                      true,
+}
+
+// gammaRoute is routeTemplate for Gamma paths, with unknown routes
+// collapsed into otherRoute
+func gammaRoute(path string) string {
+	if route := routeTemplate(path); gammaRoutes[route] {
+		return route
+	}
+	return otherRoute
+}
+
+// routeTemplate turns a request path into the endpoint name used by hooks
+// and metrics, so IDs, hashes and slugs don't create a series each
+// e.g. "/events/123" -> "/events/{id}", "/events/slug/abc" -> "/events/slug/{slug}",
+// "/positions/0xabc" -> "/positions/{hex}"
+func routeTemplate(path string) string {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segs {
+		switch {
+		case i > 0 && segs[i-1] == "slug":
+			segs[i] = "{slug}"
+		case s != "" && strings.Trim(s, "0123456789") == "":
+			segs[i] = "{id}"
+		case isHex(s):
+			segs[i] = "{hex}"
+		}
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+// isHex reports whether s is a 0x-prefixed hex string, such as an address
+// or condition ID
+func isHex(s string) bool {
+	digits, ok := strings.CutPrefix(strings.ToLower(s), "0x")
+	return ok && digits != "" && strings.Trim(digits, "0123456789abcdef") == ""
+}
@@ -0,0 +1,145 @@
+// gammago/raw_test.go
+
+package gammago
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetRaw(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	var endpoints []string
+	SetHooks(Hooks{OnRequest: func(info RequestInfo) { endpoints = append(endpoints, info.Endpoint) }})
+	defer resetMiddleware()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo":
+			w.Write([]byte(`"` + r.URL.RawQuery + `"`))
+		case "/events/42":
+			w.Write([]byte(`{"id":"42"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	SetBaseURL(srv.URL)
+	defer SetBaseURL("")
+
+	t.Run("returns the body untouched", func(t *testing.T) {
+		body, err := GetRaw(t.Context(), "/events/42", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(body) != `{"id":"42"}` {
+			t.Errorf("body = %s", body)
+		}
+	})
+
+	t.Run("passes query params", func(t *testing.T) {
+		body, err := GetRaw(t.Context(), "/echo", url.Values{"limit": {"2"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(body) != `"limit=2"` {
+			t.Errorf("body = %s, want the query echoed", body)
+		}
+	})
+
+	t.Run("non-2xx is a StatusError", func(t *testing.T) {
+		_, err := GetRaw(t.Context(), "/missing", nil)
+		var se *StatusError
+		if !errors.As(err, &se) {
+			t.Fatalf("err = %v, want *StatusError", err)
+		}
+		if se.StatusCode != http.StatusNotFound || string(se.Body) != `{"error":"not found"}` {
+			t.Errorf("StatusError = %d %s", se.StatusCode, se.Body)
+		}
+	})
+
+	if endpoints[0] != "/events/{id}" {
+		t.Errorf("endpoint = %q, want /events/{id}", endpoints[0])
+	}
+}
+
+func TestRouteTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/markets":             "/markets",
+		"/events/123":          "/events/{id}",
+		"events/123/":          "/events/{id}",
+		"/events/slug/abc-def": "/events/slug/{slug}",
+		"/tags/7/related-tags": "/tags/{id}/related-tags",
+		"/sports/market-types": "/sports/market-types",
+		"/positions/0xAbC123":  "/positions/{hex}",
+		"/markets/0x":          "/markets/0x",
+	}
+	for path, want := range tests {
+		if got := routeTemplate(path); got != want {
+			t.Errorf("routeTemplate(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestGammaRoute(t *testing.T) {
+	tests := map[string]string{
+		"/markets":                  "/markets",
+		"/events/123":               "/events/{id}",
+		"/tags/slug/nfl":            "/tags/slug/{slug}",
+		"/tags/7/related-tags/tags": "/tags/{id}/related-tags/tags",
+		"/markets/0x5f2a9c":         "other",
+		"/wp-login.php":             "other",
+		"/events/123/../../x":       "other",
+		"/":                         "other",
+	}
+	for path, want := range tests {
+		if got := gammaRoute(path); got != want {
+			t.Errorf("gammaRoute(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestSetBaseURL(t *testing.T) {
+	SetBaseURL("http://localhost:8080/")
+	defer SetBaseURL("")
+
+	got, _ := buildUrl("markets", nil)
+	if got != "http://localhost:8080/markets" {
+		t.Errorf("buildUrl = %q", got)
+	}
+
+	SetBaseURL("")
+	got, _ = buildUrl("markets", nil)
+	if got != BASE_URL+"/markets" {
+		t.Errorf("buildUrl after reset = %q", got)
+	}
+}
+
+func TestGetRawFrom(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer srv.Close()
+
+	// the Gamma base URL is left alone
+	body, err := GetRawFrom(t.Context(), srv.URL+"/", "/book", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != `{"path":"/book"}` {
+		t.Errorf("body = %s", body)
+	}
+	if getBaseURL() != BASE_URL {
+		t.Errorf("base URL changed to %q", getBaseURL())
+	}
+}
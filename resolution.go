@@ -0,0 +1,126 @@
+// gammago/resolution.go
+
+package gammago
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ErrNotResolved is returned by Market.Resolution for markets without an outcome yet
+var ErrNotResolved = errors.New("market not resolved")
+
+// UMA resolution statuses seen in Market.UMAResolutionStatus
+const (
+	UMAProposed = "proposed"
+	UMADisputed = "disputed"
+	UMAResolved = "resolved"
+)
+
+// Resolution is how a closed market settled
+type Resolution struct {
+	WinningOutcome string // empty when the market settled 50/50
+	WinningIndex   int    // index into the market's outcomes, -1 when split
+	ResolvedAt     time.Time
+	Status         string // the UMA status, e.g. UMAResolved
+	Disputed       bool   // the UMA proposal was disputed at least once
+	Bond           float64
+	Reward         float64
+}
+
+// Resolution derives the settlement of a closed market from its fields
+// The winner is the outcome priced at 1. A closed market with no such outcome
+// counts as resolved only once UMA reports it resolved, which is a split
+// ResolvedAt is ClosedTime, zero if Gamma didn't send one
+func (m Market) Resolution() (Resolution, error) {
+	if !m.Closed {
+		return Resolution{}, ErrNotResolved
+	}
+
+	var outcomes, prices []string
+	json.Unmarshal([]byte(m.Outcomes), &outcomes)
+	json.Unmarshal([]byte(m.OutcomePrices), &prices)
+
+	r := Resolution{WinningIndex: -1, ResolvedAt: m.ClosedTime, Status: m.UMAResolutionStatus}
+	for i, p := range prices {
+		if v, err := strconv.ParseFloat(p, 64); err == nil && v == 1 {
+			r.WinningIndex = i
+			if i < len(outcomes) {
+				r.WinningOutcome = outcomes[i]
+			}
+			break
+		}
+	}
+	if r.WinningIndex < 0 && r.Status != UMAResolved {
+		return Resolution{}, ErrNotResolved
+	}
+
+	var history []string
+	json.Unmarshal([]byte(m.UMAResolutionStatuses), &history)
+	r.Disputed = r.Status == UMADisputed || slices.Contains(history, UMADisputed)
+
+	r.Bond, _ = strconv.ParseFloat(m.UMABond, 64)
+	r.Reward, _ = strconv.ParseFloat(m.UMAReward, 64)
+	return r, nil
+}
+
+// ResolvedMarket is a market with its resolution
+type ResolvedMarket struct {
+	Market     Market
+	Resolution Resolution
+}
+
+// GetResolvedMarkets gets up to limit resolved markets, most recently
+// closed first, starting offset markets into the closed markets
+// tagID 0 means any tag. The since/until window applies to the resolution
+// time; a zero time leaves that end open. Pages of limit markets are fetched
+// until limit markets are found, the pages run out or the markets close
+// before since
+func GetResolvedMarkets(limit, offset, tagID int, since, until time.Time) ([]ResolvedMarket, error) {
+	params := url.Values{}
+	params.Add("closed", "true")
+	params.Add("order", "closedTime")
+	params.Add("ascending", "false")
+	params.Add("limit", strconv.Itoa(limit))
+	if tagID != 0 {
+		params.Add("tag_id", strconv.Itoa(tagID))
+	}
+
+	var resolved []ResolvedMarket
+	for len(resolved) < limit {
+		params.Set("offset", strconv.Itoa(offset))
+		reqUrl, _ := buildUrl("markets", params)
+		markets, err := genericGet[[]Market]("/markets", reqUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range markets {
+			r, err := m.Resolution()
+			if err != nil {
+				continue
+			}
+			if !since.IsZero() && r.ResolvedAt.Before(since) {
+				continue
+			}
+			if !until.IsZero() && !r.ResolvedAt.Before(until) {
+				continue
+			}
+			resolved = append(resolved, ResolvedMarket{Market: m, Resolution: r})
+			if len(resolved) == limit {
+				break
+			}
+		}
+
+		// pages are newest first, so a page ending before since is the last one needed
+		if len(markets) < limit || (!since.IsZero() && markets[len(markets)-1].ClosedTime.Before(since)) {
+			break
+		}
+		offset += len(markets)
+	}
+	return resolved, nil
+}
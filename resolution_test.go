@@ -0,0 +1,173 @@
+// gammago/resolution_test.go
+
+package gammago
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolution(t *testing.T) {
+	closedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		json   string
+		want   Resolution
+		wantOK bool
+	}{
+		{
+			name: "won",
+			json: `{"closed":true,"outcomes":"[\"Yes\", \"No\"]","outcomePrices":"[\"0\", \"1\"]",
+				"umaResolutionStatus":"resolved","umaBond":"750","umaReward":"5","closedTime":"2026-03-01 12:00:00+00"}`,
+			want:   Resolution{WinningOutcome: "No", WinningIndex: 1, ResolvedAt: closedAt, Status: UMAResolved, Bond: 750, Reward: 5},
+			wantOK: true,
+		},
+		{
+			name: "disputed before resolving",
+			json: `{"closed":true,"outcomes":"[\"Yes\", \"No\"]","outcomePrices":"[\"1\", \"0\"]","umaResolutionStatus":"resolved",
+				"umaResolutionStatuses":"[\"proposed\",\"disputed\",\"proposed\",\"resolved\"]"}`,
+			want:   Resolution{WinningOutcome: "Yes", WinningIndex: 0, Status: UMAResolved, Disputed: true},
+			wantOK: true,
+		},
+		{
+			name:   "split",
+			json:   `{"closed":true,"outcomes":"[\"Yes\", \"No\"]","outcomePrices":"[\"0.5\", \"0.5\"]","umaResolutionStatus":"resolved"}`,
+			want:   Resolution{WinningIndex: -1, Status: UMAResolved},
+			wantOK: true,
+		},
+		{
+			name: "closed, still proposed",
+			json: `{"closed":true,"outcomes":"[\"Yes\", \"No\"]","outcomePrices":"[\"0.9995\", \"0.0005\"]","umaResolutionStatus":"proposed"}`,
+		},
+		{
+			name: "open",
+			json: `{"closed":false,"outcomes":"[\"Yes\", \"No\"]","outcomePrices":"[\"1\", \"0\"]"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Market
+			if err := json.Unmarshal([]byte(tt.json), &m); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			got, err := m.Resolution()
+			if !tt.wantOK {
+				if !errors.Is(err, ErrNotResolved) {
+					t.Errorf("expected ErrNotResolved, got %+v, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.ResolvedAt.Equal(tt.want.ResolvedAt) {
+				t.Errorf("ResolvedAt = %v, want %v", got.ResolvedAt, tt.want.ResolvedAt)
+			}
+			got.ResolvedAt, tt.want.ResolvedAt = time.Time{}, time.Time{}
+			if got != tt.want {
+				t.Errorf("Resolution() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetResolvedMarkets(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[
+			{"id":"3","closed":true,"outcomes":"[\"Yes\",\"No\"]","outcomePrices":"[\"1\",\"0\"]","closedTime":"2026-03-03T00:00:00Z"},
+			{"id":"2","closed":true,"outcomes":"[\"Yes\",\"No\"]","outcomePrices":"[\"0.99\",\"0.01\"]","closedTime":"2026-03-02T00:00:00Z"},
+			{"id":"1","closed":true,"outcomes":"[\"Yes\",\"No\"]","outcomePrices":"[\"0\",\"1\"]","closedTime":"2026-02-01T00:00:00Z"}
+		]`))
+	}))
+	defer srv.Close()
+	SetBaseURL(srv.URL)
+	defer SetBaseURL("")
+
+	markets, err := GetResolvedMarkets(20, 0, 100381, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(markets) != 1 || markets[0].Market.ID != "3" || markets[0].Resolution.WinningOutcome != "Yes" {
+		t.Errorf("unexpected markets %+v", markets)
+	}
+	if query != "ascending=false&closed=true&limit=20&offset=0&order=closedTime&tag_id=100381" {
+		t.Errorf("unexpected query %q", query)
+	}
+
+	all, err := GetResolvedMarkets(20, 0, 0, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 2 || all[1].Resolution.WinningOutcome != "No" {
+		t.Errorf("unexpected markets %+v", all)
+	}
+}
+
+func TestGetResolvedMarketsPages(t *testing.T) {
+	resetHTTPClient()
+	resetMiddleware()
+
+	// newest first, as Gamma orders them; 4 is unresolved and 2 is outside until
+	pages := []string{
+		`{"id":"6","closed":true,"outcomePrices":"[\"1\",\"0\"]","closedTime":"2026-03-06T00:00:00Z"}`,
+		`{"id":"5","closed":true,"outcomePrices":"[\"1\",\"0\"]","closedTime":"2026-03-05T00:00:00Z"}`,
+		`{"id":"4","closed":true,"outcomePrices":"[\"0.5\",\"0.5\"]","closedTime":"2026-03-04T00:00:00Z"}`,
+		`{"id":"3","closed":true,"outcomePrices":"[\"0\",\"1\"]","closedTime":"2026-03-03T00:00:00Z"}`,
+		`{"id":"2","closed":true,"outcomePrices":"[\"0\",\"1\"]","closedTime":"2026-02-01T00:00:00Z"}`,
+		`{"id":"1","closed":true,"outcomePrices":"[\"0\",\"1\"]","closedTime":"2026-01-01T00:00:00Z"}`,
+	}
+	var offsets []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := min(offset+limit, len(pages))
+		w.Write([]byte("[" + strings.Join(pages[min(offset, end):end], ",") + "]"))
+	}))
+	defer srv.Close()
+	SetBaseURL(srv.URL)
+	defer SetBaseURL("")
+
+	tests := []struct {
+		name         string
+		limit        int
+		since, until time.Time
+		wantIDs      string
+		wantOffsets  string
+	}{
+		{"fills limit across pages", 2, time.Time{}, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), "5,3", "0,2"},
+		{"stops once a page passes since", 5, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Time{}, "6,5,3", "0"},
+		{"no window", 4, time.Time{}, time.Time{}, "6,5,3,2", "0,4"},
+		{"runs out of pages", 5, time.Time{}, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), "5,3,2,1", "0,5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offsets = nil
+			got, err := GetResolvedMarkets(tt.limit, 0, 0, tt.since, tt.until)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, m := range got {
+				ids = append(ids, m.Market.ID)
+			}
+			if strings.Join(ids, ",") != tt.wantIDs {
+				t.Errorf("ids = %v, want %s", ids, tt.wantIDs)
+			}
+			if strings.Join(offsets, ",") != tt.wantOffsets {
+				t.Errorf("offsets = %v, want %s", offsets, tt.wantOffsets)
+			}
+		})
+	}
+}
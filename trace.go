@@ -0,0 +1,72 @@
+// gammago/trace.go
+
+package gammago
+
+import (
+	"context"
+	"reflect"
+)
+
+// Attribute is a key/value pair attached to a span
+// Values are string, int, bool or float64
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is a single traced operation
+// It mirrors the subset of the OpenTelemetry span API the client needs,
+// so an OTel bridge can implement it without this module importing OTel
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans
+// The returned context carries the new span and is used for child spans
+// and for the outgoing request, so propagators in middleware can read it
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+var tracer Tracer
+
+// SetTracer sets the tracer used for API calls
+// Each call gets a span with a child span per attempt
+// Pass nil to disable tracing (the default)
+func SetTracer(t Tracer) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	tracer = t
+}
+
+func getTracer() Tracer {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	if tracer == nil {
+		return noopTracer{}
+	}
+	return tracer
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// resultCount is the number of elements in a decoded slice, or 1 otherwise
+func resultCount(v any) int {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice {
+		return rv.Len()
+	}
+	return 1
+}
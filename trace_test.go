@@ -0,0 +1,132 @@
+// gammago/trace_test.go
+
+package gammago
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type spanKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]any
+	errs   []error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+func (s *recordedSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	s := &recordedSpan{name: name, parent: parent, attrs: map[string]any{}}
+	s.SetAttributes(attrs...)
+	r.spans = append(r.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func TestTracing(t *testing.T) {
+	t.Run("one span per call with a child span per attempt", func(t *testing.T) {
+		resetHTTPClient()
+		resetMiddleware()
+		defer SetTracer(nil)
+
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+		}))
+		defer srv.Close()
+
+		tr := &recordingTracer{}
+		SetTracer(tr)
+
+		root := &recordedSpan{name: "caller", attrs: map[string]any{}}
+		ctx := context.WithValue(context.Background(), spanKey{}, root)
+
+		events, err := genericGetCtx[[]Event](ctx, "/events", srv.URL+"/events?tag_id=1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("got %d events, want 2", len(events))
+		}
+
+		if len(tr.spans) != 3 {
+			t.Fatalf("got %d spans, want 3 (call + 2 attempts)", len(tr.spans))
+		}
+
+		call := tr.spans[0]
+		if call.name != "gammago GET /events" || call.parent != root {
+			t.Errorf("call span = %q parent=%v, want child of caller", call.name, call.parent)
+		}
+		if call.attrs["gammago.endpoint"] != "/events" || call.attrs["gammago.params"] != "tag_id=1" {
+			t.Errorf("call span attrs = %v", call.attrs)
+		}
+		if call.attrs["gammago.result_count"] != 2 || call.attrs["http.status_code"] != 200 {
+			t.Errorf("call span result attrs = %v", call.attrs)
+		}
+
+		for i, s := range tr.spans[1:] {
+			if s.parent != call {
+				t.Errorf("attempt %d span is not a child of the call span", i)
+			}
+			if s.attrs["gammago.attempt"] != i {
+				t.Errorf("attempt attr = %v, want %d", s.attrs["gammago.attempt"], i)
+			}
+			if !s.ended {
+				t.Errorf("attempt %d span not ended", i)
+			}
+		}
+		if tr.spans[1].attrs["http.status_code"] != 429 || len(tr.spans[1].errs) != 1 {
+			t.Errorf("first attempt should record 429 error: %+v", tr.spans[1])
+		}
+		if !call.ended || len(call.errs) != 0 {
+			t.Errorf("call span ended=%v errs=%v", call.ended, call.errs)
+		}
+	})
+
+	t.Run("failed call records error on the call span", func(t *testing.T) {
+		resetHTTPClient()
+		resetMiddleware()
+		defer SetTracer(nil)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		tr := &recordingTracer{}
+		SetTracer(tr)
+
+		if _, err := genericGet[Event]("/events/{id}", srv.URL+"/events/1"); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if len(tr.spans[0].errs) != 1 {
+			t.Errorf("call span errors = %v, want 1", tr.spans[0].errs)
+		}
+	})
+}
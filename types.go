@@ -2,7 +2,10 @@
 
 package gammago
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Status string
 
@@ -19,6 +22,8 @@ type Team struct {
 	Abbreviation string `json:"abbreviation"`
 	Alias        string `json:"alias"`
 	Color        string `json:"color"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Sport struct {
@@ -28,16 +33,22 @@ type Sport struct {
 	Ordering   string `json:"ordering"`
 	Tags       string `json:"tags"`
 	Series     string `json:"series"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type MarketTypes struct {
 	MarketTypes []string `json:"marketTypes"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Tag struct {
 	ID    string `json:"id"`
 	Label string `json:"label"`
 	Slug  string `json:"slug"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Category struct {
@@ -45,6 +56,8 @@ type Category struct {
 	Label          string `json:"label"`
 	ParentCategory string `json:"parentCategory"`
 	Slug           string `json:"slug"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Chat struct {
@@ -53,6 +66,8 @@ type Chat struct {
 	ChannelName  string `json:"channelName"`
 	ChannelImage string `json:"channelImage"`
 	Live         bool   `json:"live"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Collection struct {
@@ -68,6 +83,8 @@ type Collection struct {
 	Icon           string `json:"icon"`
 	HeaderImage    string `json:"headerImage"`
 	Active         bool   `json:"active"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Market struct {
@@ -107,6 +124,31 @@ type Market struct {
 	SportsMarketType string     `json:"sportsMarketType"`
 	Line             float64    `json:"line"`
 	EventStartTime   time.Time  `json:"eventStartTime"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+
+	Closed                bool      `json:"closed"`
+	Archived              bool      `json:"archived"`
+	New                   bool      `json:"new"`
+	Featured              bool      `json:"featured"`
+	Restricted            bool      `json:"restricted"`
+	ResolvedBy            string    `json:"resolvedBy"`
+	AcceptingOrders       bool      `json:"acceptingOrders"`
+	EnableOrderBook       bool      `json:"enableOrderBook"`
+	OrderPriceMinTickSize float64   `json:"orderPriceMinTickSize"`
+	OrderMinSize          float64   `json:"orderMinSize"`
+	BestBid               float64   `json:"bestBid"`
+	BestAsk               float64   `json:"bestAsk"`
+	LastTradePrice        float64   `json:"lastTradePrice"`
+	OneDayPriceChange     float64   `json:"oneDayPriceChange"`
+	UMAResolutionStatus   string    `json:"umaResolutionStatus"`
+	UMAResolutionStatuses string    `json:"umaResolutionStatuses"`
+	NegRisk               bool      `json:"negRisk"`
+	NegRiskMarketID       string    `json:"negRiskMarketID"`
+	GroupItemTitle        string    `json:"groupItemTitle"`
+	ClosedTime            time.Time `json:"closedTime"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Series struct {
@@ -121,12 +163,16 @@ type Series struct {
 	Image        string       `json:"image"`
 	Icon         string       `json:"icon"`
 	Active       bool         `json:"active"`
-	Events       string       `json:"events"`
+	Events       []Event      `json:"events"`
 	Collections  []Collection `json:"collections"`
 	Categories   []Category   `json:"categories"`
 	Tags         []Tag        `json:"tags"`
 	CommentCount int          `json:"commentCount"`
 	Chats        []Chat       `json:"chats"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Event struct {
@@ -174,4 +220,38 @@ type Event struct {
 	Chats            []Chat       `json:"chats"`
 	SpreadsMainLine  float64      `json:"spreadsMainLine"`
 	TotalsMainLine   float64      `json:"totalsMainLine"`
+	Closed           bool         `json:"closed"`
+	Archived         bool         `json:"archived"`
+	Featured         bool         `json:"featured"`
+	Restricted       bool         `json:"restricted"`
+	OpenInterest     float64      `json:"openInterest"`
+	Competitive      float64      `json:"competitive"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type Profile struct {
+	ProxyWallet           string    `json:"proxyWallet"`
+	Name                  string    `json:"name"`
+	Pseudonym             string    `json:"pseudonym"`
+	Bio                   string    `json:"bio"`
+	ProfileImage          string    `json:"profileImage"`
+	DisplayUsernamePublic bool      `json:"displayUsernamePublic"`
+	XUsername             string    `json:"xUsername"`
+	VerifiedBadge         bool      `json:"verifiedBadge"`
+	CreatedAt             time.Time `json:"createdAt"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// DisplayName is the name Polymarket shows for the profile: the chosen
+// name when public, otherwise the pseudonym
+func (p Profile) DisplayName() string {
+	if p.DisplayUsernamePublic && p.Name != "" {
+		return p.Name
+	}
+	if p.Pseudonym != "" {
+		return p.Pseudonym
+	}
+	return p.ProxyWallet
 }
@@ -84,12 +84,16 @@ func (m Market) String() string {
 	sb.WriteString(fmt.Sprintf("  MarketType: %s\n", m.MarketType))
 	sb.WriteString(fmt.Sprintf("  SportsMarketType: %s\n", m.SportsMarketType))
 	sb.WriteString(fmt.Sprintf("  Active: %t\n", m.Active))
+	sb.WriteString(fmt.Sprintf("  Closed: %t\n", m.Closed))
 	sb.WriteString(fmt.Sprintf("  StartDate: %s\n", m.StartDate.Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("  EndDate: %s\n", m.EndDate.Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("  Volume: %s\n", m.Volume))
 	sb.WriteString(fmt.Sprintf("  Volume24hr: %.2f\n", m.Volume24hr))
 	sb.WriteString(fmt.Sprintf("  Liquidity: %s\n", m.Liquidity))
 	sb.WriteString(fmt.Sprintf("  Spread: %.2f\n", m.Spread))
+	sb.WriteString(fmt.Sprintf("  BestBid: %.3f\n", m.BestBid))
+	sb.WriteString(fmt.Sprintf("  BestAsk: %.3f\n", m.BestAsk))
+	sb.WriteString(fmt.Sprintf("  LastTradePrice: %.3f\n", m.LastTradePrice))
 	sb.WriteString(fmt.Sprintf("  Line: %.2f\n", m.Line))
 
 	if len(m.Events) > 0 {
@@ -138,6 +142,10 @@ func (s Series) String() string {
 	sb.WriteString(fmt.Sprintf("  Active: %t\n", s.Active))
 	sb.WriteString(fmt.Sprintf("  CommentCount: %d\n", s.CommentCount))
 
+	if len(s.Events) > 0 {
+		sb.WriteString(fmt.Sprintf("  Events: [%d events]\n", len(s.Events)))
+	}
+
 	if len(s.Collections) > 0 {
 		sb.WriteString(fmt.Sprintf("  Collections: [%d items]\n", len(s.Collections)))
 	}
@@ -181,12 +189,14 @@ func (e Event) String() string {
 	sb.WriteString(fmt.Sprintf("  Title: %s\n", e.Title))
 	sb.WriteString(fmt.Sprintf("  Subtitle: %s\n", e.Subtitle))
 	sb.WriteString(fmt.Sprintf("  Active: %t\n", e.Active))
+	sb.WriteString(fmt.Sprintf("  Closed: %t\n", e.Closed))
 	sb.WriteString(fmt.Sprintf("  StartDate: %s\n", e.StartDate.Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("  EndDate: %s\n", e.EndDate.Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("  Category: %s\n", e.Category))
 	sb.WriteString(fmt.Sprintf("  Subcategory: %s\n", e.Subcategory))
 	sb.WriteString(fmt.Sprintf("  Volume: %.2f\n", e.Volume))
 	sb.WriteString(fmt.Sprintf("  Volume24hr: %.2f\n", e.Volume24hr))
+	sb.WriteString(fmt.Sprintf("  OpenInterest: %.2f\n", e.OpenInterest))
 	sb.WriteString(fmt.Sprintf("  Liquidity: %.2f\n", e.Liquidity))
 	sb.WriteString(fmt.Sprintf("  NegRisk: %t\n", e.NegRisk))
 	sb.WriteString(fmt.Sprintf("  CommentsEnabled: %t\n", e.CommentsEnabled))
@@ -261,3 +271,19 @@ func (e Event) String() string {
 	sb.WriteString("}")
 	return sb.String()
 }
+
+func (p Profile) String() string {
+	var sb strings.Builder
+	sb.WriteString("Profile{\n")
+	sb.WriteString(fmt.Sprintf("  ProxyWallet: %s\n", p.ProxyWallet))
+	sb.WriteString(fmt.Sprintf("  Name: %s\n", p.Name))
+	sb.WriteString(fmt.Sprintf("  Pseudonym: %s\n", p.Pseudonym))
+	sb.WriteString(fmt.Sprintf("  Bio: %s\n", p.Bio))
+	sb.WriteString(fmt.Sprintf("  ProfileImage: %s\n", p.ProfileImage))
+	sb.WriteString(fmt.Sprintf("  DisplayUsernamePublic: %t\n", p.DisplayUsernamePublic))
+	sb.WriteString(fmt.Sprintf("  XUsername: %s\n", p.XUsername))
+	sb.WriteString(fmt.Sprintf("  VerifiedBadge: %t\n", p.VerifiedBadge))
+	sb.WriteString(fmt.Sprintf("  CreatedAt: %s\n", p.CreatedAt.Format("2006-01-02 15:04:05")))
+	sb.WriteString("}")
+	return sb.String()
+}
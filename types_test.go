@@ -0,0 +1,137 @@
+// gammago/types_test.go
+
+package gammago
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func readFixture[T any](t *testing.T, name string) T {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatalf("decoding %s: %v", name, err)
+	}
+	return v
+}
+
+func TestDecodeMarketFixture(t *testing.T) {
+	m := readFixture[Market](t, "market.json")
+
+	if !m.Featured || !m.Restricted || m.Closed || m.Archived || m.New {
+		t.Errorf("flags: featured %t restricted %t closed %t archived %t new %t", m.Featured, m.Restricted, m.Closed, m.Archived, m.New)
+	}
+	if !m.AcceptingOrders || !m.EnableOrderBook || !m.NegRisk {
+		t.Errorf("trading flags: accepting %t orderbook %t negRisk %t", m.AcceptingOrders, m.EnableOrderBook, m.NegRisk)
+	}
+	if m.NegRiskMarketID != "0x7a0e1e06cb46b7e3f0e7f7d2a6c8b4e5f1d2c3b4a5968778695a4b3c2d1e0f00" {
+		t.Errorf("NegRiskMarketID = %q", m.NegRiskMarketID)
+	}
+	if m.OrderPriceMinTickSize != 0.001 || m.OrderMinSize != 5 {
+		t.Errorf("order limits: tick %v min %v", m.OrderPriceMinTickSize, m.OrderMinSize)
+	}
+	if m.BestBid != 0.86 || m.BestAsk != 0.87 || m.LastTradePrice != 0.87 || m.OneDayPriceChange != -0.0215 {
+		t.Errorf("prices: bid %v ask %v last %v change %v", m.BestBid, m.BestAsk, m.LastTradePrice, m.OneDayPriceChange)
+	}
+	if m.ResolvedBy != "0x6A9D222616C90FcA5754cd1333cFD9b7fb6a4F74" || m.UMAResolutionStatus != "proposed" || m.GroupItemTitle != "25 bps decrease" {
+		t.Errorf("resolution: by %q status %q group %q", m.ResolvedBy, m.UMAResolutionStatus, m.GroupItemTitle)
+	}
+	if want := time.Date(2025, 12, 10, 19, 2, 31, 0, time.UTC); !m.ClosedTime.Equal(want) {
+		t.Errorf("ClosedTime = %v, want %v", m.ClosedTime, want)
+	}
+	if !m.Events[0].NegRisk || m.Events[0].Closed {
+		t.Errorf("nested event: %+v", m.Events[0])
+	}
+	if _, ok := m.Extra["volumeNum"]; !ok {
+		t.Errorf("undeclared fields should be kept, Extra = %v", m.Extra)
+	}
+	if _, ok := m.Extra["closedTime"]; ok {
+		t.Error("closedTime should not be in Extra")
+	}
+}
+
+func TestDecodeEventFixture(t *testing.T) {
+	e := readFixture[Event](t, "event.json")
+
+	if e.Closed || e.Archived || !e.Featured || !e.Restricted {
+		t.Errorf("flags: closed %t archived %t featured %t restricted %t", e.Closed, e.Archived, e.Featured, e.Restricted)
+	}
+	if e.OpenInterest != 4310921.55 || e.Competitive != 0.9412037735441508 {
+		t.Errorf("OpenInterest %v Competitive %v", e.OpenInterest, e.Competitive)
+	}
+	if len(e.Markets) != 2 {
+		t.Fatalf("len(Markets) = %d", len(e.Markets))
+	}
+	if !e.Markets[0].ClosedTime.IsZero() || e.Markets[0].BestAsk != 0.87 {
+		t.Errorf("open market: %+v", e.Markets[0])
+	}
+	closed := e.Markets[1]
+	if want := time.Date(2025, 12, 10, 19, 2, 31, 123e6, time.UTC); !closed.Closed || !closed.ClosedTime.Equal(want) {
+		t.Errorf("closed market: closed %t at %v", closed.Closed, closed.ClosedTime)
+	}
+}
+
+func TestMarketRoundTripFixture(t *testing.T) {
+	m := readFixture[Market](t, "market.json")
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again Market
+	if err := json.Unmarshal(b, &again); err != nil {
+		t.Fatalf("decoding re-encoded market: %v", err)
+	}
+	if !again.ClosedTime.Equal(m.ClosedTime) || again.BestBid != m.BestBid || len(again.Extra) != len(m.Extra) {
+		t.Errorf("round trip changed the market:\n%s", b)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := map[string]time.Time{
+		"":                          {},
+		"2025-12-10T19:02:31Z":      time.Date(2025, 12, 10, 19, 2, 31, 0, time.UTC),
+		"2025-12-10 19:02:31+00":    time.Date(2025, 12, 10, 19, 2, 31, 0, time.UTC),
+		"2025-12-10 21:02:31+02:00": time.Date(2025, 12, 10, 19, 2, 31, 0, time.UTC),
+		"2025-12-10":                time.Date(2025, 12, 10, 0, 0, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := parseTime(in)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseTime(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseTime("yesterday"); err == nil {
+		t.Error("parseTime should reject garbage")
+	}
+}
+
+func TestDecodeSeriesFixture(t *testing.T) {
+	s := readFixture[Series](t, "series.json")
+
+	if s.ID != "10345" || s.Slug != "nba-2026" || !s.Active || s.CommentCount != 1412 {
+		t.Errorf("series fields: %+v", s)
+	}
+	if len(s.Events) != 2 {
+		t.Fatalf("len(Events) = %d, want 2", len(s.Events))
+	}
+	if e := s.Events[0]; e.ID != "83012" || e.Title != "Lakers vs. Celtics" || e.Closed {
+		t.Errorf("first event: %+v", e)
+	}
+	if e := s.Events[1]; !e.Closed || e.Volume != 2210987.01 {
+		t.Errorf("second event: closed %t volume %v", e.Closed, e.Volume)
+	}
+	if len(s.Tags) != 2 || s.Tags[1].Label != "NBA" || len(s.Chats) != 1 {
+		t.Errorf("tags %+v chats %+v", s.Tags, s.Chats)
+	}
+	if _, ok := s.Extra["volume24hr"]; !ok {
+		t.Errorf("undeclared fields should be kept, Extra = %v", s.Extra)
+	}
+}
@@ -3,11 +3,14 @@
 package gammago
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -17,46 +20,284 @@ const (
 	baseDelay  = 800 * time.Millisecond
 )
 
+// ErrResponseTooLarge is returned when a body exceeds the limit set by SetMaxResponseSize
+var ErrResponseTooLarge = errors.New("response body exceeds maximum size")
+
+// StatusError is returned when the API answers with a non-2xx status
+// after all retries. Body is the response body as sent
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status code: %d, %s", e.StatusCode, e.Body)
+}
+
+var maxResponseSize int64
+
+// SetMaxResponseSize caps the number of body bytes read per response
+// Oversized responses fail with ErrResponseTooLarge and are not retried
+// Pass 0 to remove the limit (the default)
+func SetMaxResponseSize(n int64) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	maxResponseSize = n
+}
+
+func getMaxResponseSize() int64 {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return maxResponseSize
+}
+
+// decodeFunc consumes a 2xx body and returns the number of results decoded
+type decodeFunc func(body io.Reader) (int, error)
+
+// decodeError marks a failure as a JSON decode failure rather than a read failure
+// final means results were already handed out, so the request can't be retried
+type decodeError struct {
+	body  []byte
+	err   error
+	final bool
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+func (e *decodeError) Unwrap() error { return e.err }
+
+// callbackError carries an error returned by a stream callback back to the caller untouched
+type callbackError struct {
+	err error
+}
+
+func (e *callbackError) Error() string { return e.err.Error() }
+
 // Send a GET request to a given URL
 // Add parameter headers
 // Attempt to unmarshal the response into T
-func genericGet[T any](url string) (T, error) {
+// endpoint is the route template reported to hooks
+func genericGet[T any](endpoint, url string) (T, error) {
+	return genericGetCtx[T](context.Background(), endpoint, url)
+}
+
+// genericGetCtx is genericGet with a parent context for tracing and cancellation
+func genericGetCtx[T any](ctx context.Context, endpoint, url string) (T, error) {
 	var result T
+	err := doGet(ctx, endpoint, url, func(r io.Reader) (int, error) {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+		// check drift first, so a type change that breaks decoding is recorded
+		var mismatches []Drift
+		if getStrictMode() {
+			mismatches, _ = drift.check(endpoint, reflect.TypeFor[T](), body)
+		}
+		var v T
+		if err := json.Unmarshal(body, &v); err != nil {
+			return 0, &decodeError{body: body, err: withDrift(err, mismatches)}
+		}
+		result = v
+		return resultCount(v), nil
+	})
+	return result, err
+}
+
+// genericStream sends a GET request whose body is a JSON array
+// and passes each element to fn as it is decoded, without buffering the body
+// Once an element has been passed to fn the request is no longer retried
+// An error returned by fn stops decoding and is returned as is
+func genericStream[T any](ctx context.Context, endpoint, url string, fn func(T) error) error {
+	err := doGet(ctx, endpoint, url, func(r io.Reader) (int, error) {
+		dec := json.NewDecoder(r)
+
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, &decodeError{err: err}
+		}
+		if d, ok := tok.(json.Delim); !ok || d != '[' {
+			return 0, &decodeError{err: fmt.Errorf("expected JSON array, got %v", tok)}
+		}
+
+		strict := getStrictMode()
+		n := 0
+		for dec.More() {
+			var v T
+			var err error
+			if strict {
+				// keep the raw element so it can be checked for drift
+				var raw json.RawMessage
+				if err = dec.Decode(&raw); err == nil {
+					mismatches, _ := drift.check(endpoint, reflect.TypeFor[[]T](), append(append([]byte("["), raw...), ']'))
+					if err = json.Unmarshal(raw, &v); err != nil {
+						err = withDrift(err, mismatches)
+					}
+				}
+			} else {
+				err = dec.Decode(&v)
+			}
+			if err != nil {
+				return n, &decodeError{err: err, final: n > 0}
+			}
+			n++
+			if err := fn(v); err != nil {
+				return n, &callbackError{err: err}
+			}
+		}
+
+		if _, err := dec.Token(); err != nil {
+			return n, &decodeError{err: err, final: n > 0}
+		}
+		return n, nil
+	})
+
+	var cbErr *callbackError
+	if errors.As(err, &cbErr) {
+		return cbErr.err
+	}
+	return err
+}
+
+// doGet runs the request/retry loop shared by genericGet and genericStream
+// Middleware, hooks, logging, metrics and tracing all hang off this loop
+func doGet(ctx context.Context, endpoint, url string, decode decodeFunc) (err error) {
 	var lastErr error
 
-	client := getHTTPClient()
+	doer := getDoer()
+	h := getHooks()
+	l := getLogger()
+	m := getMetrics()
+	tr := getTracer()
+	limit := getMaxResponseSize()
+
+	ctx, span := tr.Start(ctx, "gammago GET "+endpoint,
+		Attribute{"gammago.endpoint", endpoint},
+		Attribute{"gammago.params", redactQuery(url)},
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
 
+	attempts := 0
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
 			// exponential backoff + jitter
 			delay := baseDelay * time.Duration(1<<attempt)
 			jitter := time.Duration(time.Now().UnixNano()%100) * time.Millisecond
-			time.Sleep(delay + jitter)
+			retry := RetryInfo{
+				RequestInfo: RequestInfo{Endpoint: endpoint, URL: url, Attempt: attempt},
+				Delay:       delay + jitter,
+				Reason:      lastErr,
+			}
+			logRetry(l, retry)
+			m.observeRetry(endpoint, retry.Delay)
+			if h.OnRetry != nil {
+				h.OnRetry(retry)
+			}
+
+			select {
+			case <-time.After(delay + jitter):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		}
+		attempts++
 
-		req, err := http.NewRequest(http.MethodGet, url, nil)
+		attemptCtx, attemptSpan := tr.Start(ctx, "gammago attempt",
+			Attribute{"gammago.endpoint", endpoint},
+			Attribute{"gammago.attempt", attempt},
+		)
+
+		req, err := http.NewRequestWithContext(attemptCtx, http.MethodGet, url, nil)
 		if err != nil {
-			return result, fmt.Errorf("create request failed: %w", err)
+			attemptSpan.RecordError(err)
+			attemptSpan.End()
+			return fmt.Errorf("create request failed: %w", err)
 		}
 
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
+		info := RequestInfo{Endpoint: endpoint, URL: url, Attempt: attempt, Start: time.Now()}
+		if h.OnRequest != nil {
+			h.OnRequest(info)
 		}
 
-		body, err := io.ReadAll(resp.Body)
+		resp, err := doer.Do(req)
 		if err != nil {
-			lastErr = fmt.Errorf("read body failed: %w", err)
+			lastErr = err
+			elapsed := time.Since(info.Start)
+			logAttempt(l, info, 0, 0, err)
+			m.observeResponse(endpoint, 0, elapsed)
+			if h.OnResponse != nil {
+				h.OnResponse(ResponseInfo{RequestInfo: info, Duration: elapsed, Err: err})
+			}
+			attemptSpan.RecordError(err)
+			attemptSpan.End()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			continue
 		}
 
+		body := &bodyReader{r: resp.Body, limit: limit}
+		var errBody []byte
+		var decodeErr error
+		count := 0
+		ok := resp.StatusCode >= 200 && resp.StatusCode < 300
+		if ok {
+			count, decodeErr = decode(body)
+		} else {
+			errBody, _ = io.ReadAll(body)
+		}
 		// defer won't hit on retries
 		resp.Body.Close()
 
+		elapsed := time.Since(info.Start)
+		logAttempt(l, info, resp.StatusCode, int(body.n), body.err)
+		m.observeResponse(endpoint, resp.StatusCode, elapsed)
+		if h.OnResponse != nil {
+			h.OnResponse(ResponseInfo{
+				RequestInfo: info,
+				StatusCode:  resp.StatusCode,
+				Bytes:       int(body.n),
+				Duration:    elapsed,
+				Err:         body.err,
+			})
+		}
+		attemptSpan.SetAttributes(Attribute{"http.status_code", resp.StatusCode})
+		span.SetAttributes(Attribute{"http.status_code", resp.StatusCode})
+
+		var de *decodeError
+		final := errors.As(decodeErr, &de) && de.final
+
+		var cbErr *callbackError
+		if errors.As(decodeErr, &cbErr) {
+			attemptSpan.End()
+			return cbErr
+		}
+
+		if errors.Is(body.err, ErrResponseTooLarge) {
+			attemptSpan.RecordError(body.err)
+			attemptSpan.End()
+			return fmt.Errorf("%s: %w (limit %d bytes)", endpoint, ErrResponseTooLarge, limit)
+		}
+
+		if body.err != nil {
+			lastErr = fmt.Errorf("read body failed: %w", body.err)
+			attemptSpan.RecordError(lastErr)
+			attemptSpan.End()
+			if final {
+				break
+			}
+			continue
+		}
+
 		// retry certain statuses
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			lastErr = fmt.Errorf("status code: %d, %s", resp.StatusCode, body)
+		if !ok {
+			lastErr = &StatusError{StatusCode: resp.StatusCode, Body: errBody}
+			attemptSpan.RecordError(lastErr)
+			attemptSpan.End()
 
 			if !shouldRetry(resp.StatusCode, attempt) {
 				break
@@ -64,19 +305,66 @@ func genericGet[T any](url string) (T, error) {
 			continue
 		}
 
-		if err = json.Unmarshal(body, &result); err != nil {
-			lastErr = fmt.Errorf("json unmarshal failed: %w", err)
+		if decodeErr != nil {
+			lastErr = fmt.Errorf("json unmarshal failed: %w", decodeErr)
+			m.observeDecodeFailure(endpoint)
+			if h.OnDecodeError != nil {
+				var raw []byte
+				if de != nil {
+					raw = de.body
+				}
+				h.OnDecodeError(DecodeErrorInfo{RequestInfo: info, Body: raw, Err: decodeErr})
+			}
+			attemptSpan.RecordError(lastErr)
+			attemptSpan.End()
+			if final {
+				break
+			}
 			continue
 		}
 
-		return result, nil
+		attemptSpan.End()
+		span.SetAttributes(Attribute{"gammago.result_count", count})
+		return nil
 	}
 
 	if lastErr == nil {
-		lastErr = fmt.Errorf("request failed after %d attempts (unknown reason)", maxRetries)
+		lastErr = fmt.Errorf("request failed after %d attempts (unknown reason)", attempts)
+	}
+
+	logExhausted(l, endpoint, url, attempts, lastErr)
+
+	return fmt.Errorf("%w (after %d attempts)", lastErr, attempts)
+}
+
+// bodyReader counts bytes read, enforces the size limit
+// and remembers the first error from the underlying body
+type bodyReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+	err   error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.limit > 0 && int64(len(p)) > b.limit-b.n+1 {
+		p = p[:b.limit-b.n+1]
 	}
 
-	return result, fmt.Errorf("%w (after %d attempts)", lastErr, maxRetries)
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	if b.limit > 0 && b.n > b.limit {
+		b.n = b.limit
+		b.err = ErrResponseTooLarge
+		return n, b.err
+	}
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
 }
 
 func shouldRetry(status int, attempt int) bool {
@@ -96,11 +384,16 @@ func shouldRetry(status int, attempt int) bool {
 // buildUrl constructs a full URL from base + endpoint + query params.
 // Returns error on invalid base URL or malformed input.
 func buildUrl(endpoint string, params url.Values) (string, error) {
-	base := strings.TrimRight(BASE_URL, "/") // normalize base
+	return joinUrl(getBaseURL(), endpoint, params)
+}
+
+// joinUrl is buildUrl against an explicit base
+func joinUrl(base, endpoint string, params url.Values) (string, error) {
+	base = strings.TrimRight(base, "/") // normalize base
 
 	u, err := url.Parse(base)
 	if err != nil {
-		return "", fmt.Errorf("invalid base URL %q: %w", BASE_URL, err)
+		return "", fmt.Errorf("invalid base URL %q: %w", base, err)
 	}
 
 	// Clean endpoint: remove leading/trailing slashes
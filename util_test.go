@@ -3,7 +3,11 @@
 package gammago
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -114,4 +118,92 @@ func TestUtils(t *testing.T) {
 			})
 		}
 	})
+	t.Run("genericStream", func(t *testing.T) {
+		resetHTTPClient()
+		resetMiddleware()
+
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			switch r.URL.Path {
+			case "/markets":
+				w.Write([]byte(`[{"id":"1","question":"a"},{"id":"2","question":"b"},{"id":"3","question":"c"}]`))
+			case "/broken":
+				w.Write([]byte(`[{"id":"1"},{"id":`))
+			case "/object":
+				w.Write([]byte(`{"id":"1"}`))
+			}
+		}))
+		defer srv.Close()
+
+		t.Run("yields each element in order", func(t *testing.T) {
+			var ids []string
+			err := genericStream(t.Context(), "/markets", srv.URL+"/markets", func(m Market) error {
+				ids = append(ids, m.ID)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(ids, ",") != "1,2,3" {
+				t.Errorf("ids = %v, want [1 2 3]", ids)
+			}
+		})
+
+		t.Run("callback error stops the stream", func(t *testing.T) {
+			stop := errors.New("stop")
+			seen := 0
+			err := genericStream(t.Context(), "/markets", srv.URL+"/markets", func(m Market) error {
+				seen++
+				return stop
+			})
+			if err != stop {
+				t.Errorf("err = %v, want callback error", err)
+			}
+			if seen != 1 {
+				t.Errorf("callback ran %d times, want 1", seen)
+			}
+		})
+
+		t.Run("no retry after elements were yielded", func(t *testing.T) {
+			calls = 0
+			seen := 0
+			err := genericStream(t.Context(), "/broken", srv.URL+"/broken", func(m Market) error {
+				seen++
+				return nil
+			})
+			if err == nil {
+				t.Fatal("expected error for truncated body")
+			}
+			if calls != 1 || seen != 1 {
+				t.Errorf("calls = %d, seen = %d, want 1 and 1", calls, seen)
+			}
+		})
+
+		t.Run("non-array body is a decode error", func(t *testing.T) {
+			SetMaxResponseSize(0)
+			err := genericStream(t.Context(), "/object", srv.URL+"/object", func(m Market) error { return nil })
+			if err == nil || !strings.Contains(err.Error(), "expected JSON array") {
+				t.Errorf("err = %v, want array error", err)
+			}
+		})
+
+		t.Run("max response size", func(t *testing.T) {
+			defer SetMaxResponseSize(0)
+			SetMaxResponseSize(16)
+
+			calls = 0
+			err := genericStream(t.Context(), "/markets", srv.URL+"/markets", func(m Market) error { return nil })
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("stream err = %v, want ErrResponseTooLarge", err)
+			}
+			_, err = genericGet[[]Market]("/markets", srv.URL+"/markets")
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("get err = %v, want ErrResponseTooLarge", err)
+			}
+			if calls != 2 {
+				t.Errorf("calls = %d, want 2 (oversized bodies are not retried)", calls)
+			}
+		})
+	})
 }
@@ -0,0 +1,47 @@
+// gammago/watch/query.go
+
+package watch
+
+import (
+	"context"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// MarketsByID watches a fixed set of markets
+func MarketsByID(ids ...int) Query[gamma.Market] {
+	return func(ctx context.Context) ([]gamma.Market, error) {
+		var markets []gamma.Market
+		for _, id := range ids {
+			m, err := gamma.GetMarketByIDCtx(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			markets = append(markets, m...)
+		}
+		return markets, nil
+	}
+}
+
+// EventsByTag watches the events under a tag
+func EventsByTag(tagID int, includeRelated bool) Query[gamma.Event] {
+	return func(ctx context.Context) ([]gamma.Event, error) {
+		return gamma.GetEventsByTagCtx(ctx, tagID, includeRelated)
+	}
+}
+
+// MarketsByTag watches every market of the events under a tag, so new
+// markets show up as Created
+func MarketsByTag(tagID int, includeRelated bool) Query[gamma.Market] {
+	return func(ctx context.Context) ([]gamma.Market, error) {
+		events, err := gamma.GetEventsByTagCtx(ctx, tagID, includeRelated)
+		if err != nil {
+			return nil, err
+		}
+		var markets []gamma.Market
+		for _, e := range events {
+			markets = append(markets, e.Markets...)
+		}
+		return markets, nil
+	}
+}
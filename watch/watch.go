@@ -0,0 +1,264 @@
+// gammago/watch/watch.go
+
+// Package watch polls a Gamma query and emits what changed between polls
+// Snapshots are diffed by ID into Created, Updated, Closed, ActiveChanged
+// and Removed changes, with the fields that differ listed on each update
+package watch
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// ChangeType is the kind of change seen between two polls
+type ChangeType int
+
+const (
+	Created ChangeType = iota + 1
+	Updated
+	Closed
+	Removed
+	ActiveChanged
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case Created:
+		return "created"
+	case Updated:
+		return "updated"
+	case Closed:
+		return "closed"
+	case Removed:
+		return "removed"
+	case ActiveChanged:
+		return "active_changed"
+	}
+	return "unknown"
+}
+
+// FieldChange is one field that differs between the old and new value
+// Field is the Go struct field name, e.g. "OutcomePrices"
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Change is one difference between consecutive snapshots
+// Old is the zero value for Created and New is the zero value for Removed
+// Closed is sent instead of Updated when the value becomes closed, and
+// ActiveChanged when only its active flag flips
+type Change[T any] struct {
+	Type   ChangeType
+	ID     string
+	Old    T
+	New    T
+	Fields []FieldChange
+	At     time.Time
+}
+
+// Query fetches the current snapshot being watched
+type Query[T any] func(ctx context.Context) ([]T, error)
+
+// DefaultInterval is used when Watcher.Interval is 0
+const DefaultInterval = 30 * time.Second
+
+// Watcher polls Query every Interval and sends changes on the channel
+// returned by Run. It doesn't poll again until the previous poll's changes
+// have been received, so a slow consumer slows the watcher down rather
+// than growing a queue
+type Watcher[T any] struct {
+	Query    Query[T]
+	Interval time.Duration
+
+	// ID pulls the identity out of a value; Closed and Active, when set,
+	// pull out the flags behind Closed and ActiveChanged changes
+	// Gamma keeps active=true on closed markets, so the two are separate
+	ID     func(T) string
+	Closed func(T) bool
+	Active func(T) bool
+
+	// Ignore lists fields left out of the diff, e.g. "UpdatedAt"
+	// A change to only ignored fields sends nothing
+	Ignore []string
+
+	// Buffer is the capacity of the change channel
+	Buffer int
+
+	// EmitInitial sends Created for everything in the first poll
+	// By default the first poll only sets the baseline
+	EmitInitial bool
+
+	// OnError is called when a poll fails; the watcher keeps polling and
+	// diffs the next good poll against the last good one
+	OnError func(error)
+}
+
+// NewMarketWatcher returns a Watcher for markets that ignores UpdatedAt
+// and undeclared fields in Extra, many of which move with every trade
+func NewMarketWatcher(query Query[gamma.Market], interval time.Duration) *Watcher[gamma.Market] {
+	return &Watcher[gamma.Market]{
+		Query:    query,
+		Interval: interval,
+		ID:       func(m gamma.Market) string { return m.ID },
+		Closed:   func(m gamma.Market) bool { return m.Closed },
+		Active:   func(m gamma.Market) bool { return m.Active },
+		Ignore:   []string{"UpdatedAt", "Extra"},
+	}
+}
+
+// NewEventWatcher returns a Watcher for events that ignores UpdatedAt
+// and Extra
+func NewEventWatcher(query Query[gamma.Event], interval time.Duration) *Watcher[gamma.Event] {
+	return &Watcher[gamma.Event]{
+		Query:    query,
+		Interval: interval,
+		ID:       func(e gamma.Event) string { return e.ID },
+		Closed:   func(e gamma.Event) bool { return e.Closed },
+		Active:   func(e gamma.Event) bool { return e.Active },
+		Ignore:   []string{"UpdatedAt", "Extra"},
+	}
+}
+
+// Run starts polling and returns the change channel
+// The channel is closed once ctx is cancelled
+func (w *Watcher[T]) Run(ctx context.Context) <-chan Change[T] {
+	out := make(chan Change[T], w.Buffer)
+	go w.loop(ctx, out)
+	return out
+}
+
+func (w *Watcher[T]) loop(ctx context.Context, out chan<- Change[T]) {
+	defer close(out)
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev map[string]T
+	for {
+		items, err := w.Query(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if w.OnError != nil {
+				w.OnError(err)
+			}
+		} else {
+			next := w.index(items)
+			if prev != nil || w.EmitInitial {
+				for _, c := range w.diff(prev, next, items, time.Now()) {
+					select {
+					case out <- c:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			prev = next
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Watcher[T]) index(items []T) map[string]T {
+	m := make(map[string]T, len(items))
+	for _, v := range items {
+		m[w.ID(v)] = v
+	}
+	return m
+}
+
+// diff compares two snapshots. Changes follow the order of the new
+// query result, with removals last in the old snapshot's ID order
+func (w *Watcher[T]) diff(prev, next map[string]T, items []T, at time.Time) []Change[T] {
+	var changes []Change[T]
+
+	for _, v := range items {
+		id := w.ID(v)
+		old, ok := prev[id]
+		if !ok {
+			changes = append(changes, Change[T]{Type: Created, ID: id, New: v, At: at})
+			continue
+		}
+
+		fields := diffFields(old, v, w.Ignore)
+		if len(fields) == 0 {
+			continue
+		}
+		typ := Updated
+		switch {
+		case w.Closed != nil && !w.Closed(old) && w.Closed(v):
+			typ = Closed
+		case w.Active != nil && w.Active(old) != w.Active(v):
+			typ = ActiveChanged
+		}
+		changes = append(changes, Change[T]{Type: typ, ID: id, Old: old, New: v, Fields: fields, At: at})
+	}
+
+	for _, id := range sortedKeys(prev) {
+		if _, ok := next[id]; !ok {
+			changes = append(changes, Change[T]{Type: Removed, ID: id, Old: prev[id], At: at})
+		}
+	}
+	return changes
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffFields lists the top-level struct fields that differ
+// Non-struct values are compared whole and reported as field ""
+func diffFields[T any](old, new T, ignore []string) []FieldChange {
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+	if ov.Kind() != reflect.Struct {
+		if reflect.DeepEqual(old, new) {
+			return nil
+		}
+		return []FieldChange{{Old: old, New: new}}
+	}
+
+	var fields []FieldChange
+	typ := ov.Type()
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if !f.IsExported() || slices.Contains(ignore, f.Name) {
+			continue
+		}
+		a, b := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if !equal(a, b) {
+			fields = append(fields, FieldChange{Field: f.Name, Old: a, New: b})
+		}
+	}
+	return fields
+}
+
+// equal is reflect.DeepEqual except times compare by instant, since
+// decoding the same timestamp twice can give different *Location values
+func equal(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		return ta.Equal(b.(time.Time))
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,239 @@
+// gammago/watch/watch_test.go
+
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+// scripted returns one snapshot per poll, repeating the last one
+type scripted struct {
+	mu    sync.Mutex
+	polls [][]gamma.Market
+	errs  []error
+	n     int
+}
+
+func (s *scripted) query(ctx context.Context) ([]gamma.Market, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := min(s.n, len(s.polls)-1)
+	s.n++
+	if i < len(s.errs) && s.errs[i] != nil {
+		return nil, s.errs[i]
+	}
+	return s.polls[i], nil
+}
+
+func (s *scripted) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.n
+}
+
+func recv(t *testing.T, ch <-chan Change[gamma.Market]) Change[gamma.Market] {
+	t.Helper()
+	select {
+	case c, ok := <-ch:
+		if !ok {
+			t.Fatal("Channel closed early")
+		}
+		return c
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for change")
+	}
+	return Change[gamma.Market]{}
+}
+
+func TestWatcher(t *testing.T) {
+	t.Run("created updated closed removed", func(t *testing.T) {
+		s := &scripted{polls: [][]gamma.Market{
+			{
+				{ID: "1", Active: true, OutcomePrices: `["0.4", "0.6"]`},
+				{ID: "2", Active: true},
+				{ID: "3", Active: true},
+				{ID: "5", Active: true},
+			},
+			{
+				{ID: "1", Active: true, OutcomePrices: `["0.5", "0.5"]`},
+				{ID: "2", Active: true, Closed: true},
+				{ID: "5", Active: false},
+				{ID: "4", Active: true},
+			},
+		}}
+		w := NewMarketWatcher(s.query, time.Millisecond)
+		ch := w.Run(t.Context())
+
+		c := recv(t, ch)
+		if c.Type != Updated || c.ID != "1" || len(c.Fields) != 1 || c.Fields[0].Field != "OutcomePrices" {
+			t.Errorf("Expected price update on 1, got %v %s %+v", c.Type, c.ID, c.Fields)
+		}
+		if c.Fields[0].Old != `["0.4", "0.6"]` || c.Fields[0].New != `["0.5", "0.5"]` {
+			t.Errorf("Expected old and new prices, got %+v", c.Fields[0])
+		}
+
+		// Gamma leaves active=true on closed markets
+		c = recv(t, ch)
+		if c.Type != Closed || c.ID != "2" || c.Old.Closed || !c.New.Closed || !c.New.Active {
+			t.Errorf("Expected 2 closed, got %v %s", c.Type, c.ID)
+		}
+
+		c = recv(t, ch)
+		if c.Type != ActiveChanged || c.ID != "5" || !c.Old.Active || c.New.Active {
+			t.Errorf("Expected 5 active changed, got %v %s", c.Type, c.ID)
+		}
+
+		c = recv(t, ch)
+		if c.Type != Created || c.ID != "4" {
+			t.Errorf("Expected 4 created, got %v %s", c.Type, c.ID)
+		}
+
+		c = recv(t, ch)
+		if c.Type != Removed || c.ID != "3" || c.Old.ID != "3" {
+			t.Errorf("Expected 3 removed, got %v %s", c.Type, c.ID)
+		}
+	})
+
+	t.Run("ignored fields and unchanged values send nothing", func(t *testing.T) {
+		t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		s := &scripted{polls: [][]gamma.Market{
+			{{ID: "1", EndDate: t0, UpdatedAt: t0}},
+			{{ID: "1", EndDate: t0.In(time.FixedZone("", 0)), UpdatedAt: t0.Add(time.Hour)}},
+			{{ID: "1", EndDate: t0, Extra: map[string]json.RawMessage{"bestBid": json.RawMessage(`0.41`)}}},
+			{{ID: "1", EndDate: t0, Spread: 0.01}},
+		}}
+		w := NewMarketWatcher(s.query, time.Millisecond)
+		ch := w.Run(t.Context())
+
+		c := recv(t, ch)
+		if c.Type != Updated || len(c.Fields) != 1 || c.Fields[0].Field != "Spread" {
+			t.Errorf("Expected only the spread update, got %v %+v", c.Type, c.Fields)
+		}
+	})
+
+	t.Run("emit initial", func(t *testing.T) {
+		s := &scripted{polls: [][]gamma.Market{{{ID: "1"}, {ID: "2"}}}}
+		w := NewMarketWatcher(s.query, time.Hour)
+		w.EmitInitial = true
+		ch := w.Run(t.Context())
+
+		if c := recv(t, ch); c.Type != Created || c.ID != "1" {
+			t.Errorf("Expected 1 created, got %v %s", c.Type, c.ID)
+		}
+		if c := recv(t, ch); c.Type != Created || c.ID != "2" {
+			t.Errorf("Expected 2 created, got %v %s", c.Type, c.ID)
+		}
+	})
+
+	t.Run("errors keep the last good snapshot", func(t *testing.T) {
+		var mu sync.Mutex
+		var errs []error
+		s := &scripted{
+			polls: [][]gamma.Market{{{ID: "1"}}, nil, {{ID: "1"}, {ID: "2"}}},
+			errs:  []error{nil, errors.New("boom")},
+		}
+		w := NewMarketWatcher(s.query, time.Millisecond)
+		w.OnError = func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+		ch := w.Run(t.Context())
+
+		// a failed poll must not look like everything was removed
+		if c := recv(t, ch); c.Type != Created || c.ID != "2" {
+			t.Errorf("Expected 2 created, got %v %s", c.Type, c.ID)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(errs) != 1 {
+			t.Errorf("Expected 1 error, got %v", errs)
+		}
+	})
+
+	t.Run("slow consumer holds back polling", func(t *testing.T) {
+		polls := make([][]gamma.Market, 20)
+		for i := range polls {
+			polls[i] = []gamma.Market{{ID: "1", Spread: float64(i)}}
+		}
+		s := &scripted{polls: polls}
+		w := NewMarketWatcher(s.query, time.Millisecond)
+		ch := w.Run(t.Context())
+
+		time.Sleep(50 * time.Millisecond)
+		// one baseline poll, one poll whose change is blocked on the channel
+		if n := s.count(); n != 2 {
+			t.Errorf("Expected 2 polls while nobody reads, got %d", n)
+		}
+		if c := recv(t, ch); c.New.Spread != 1 {
+			t.Errorf("Expected the first update, got spread %v", c.New.Spread)
+		}
+	})
+
+	t.Run("cancel closes the channel", func(t *testing.T) {
+		s := &scripted{polls: [][]gamma.Market{{{ID: "1"}}}}
+		ctx, cancel := context.WithCancel(t.Context())
+		ch := NewMarketWatcher(s.query, time.Millisecond).Run(ctx)
+		cancel()
+
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Error("Expected no changes")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Channel not closed after cancel")
+		}
+	})
+}
+
+func TestChangeTypeString(t *testing.T) {
+	for typ, want := range map[ChangeType]string{Created: "created", Updated: "updated", Closed: "closed", Removed: "removed", ActiveChanged: "active_changed", 0: "unknown"} {
+		if got := typ.String(); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+}
+
+func TestQueriesCancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	gamma.SetBaseURL(srv.URL)
+	defer gamma.SetBaseURL("")
+
+	queries := map[string]func(context.Context) error{
+		"MarketsByID":  func(ctx context.Context) error { _, err := MarketsByID(1)(ctx); return err },
+		"EventsByTag":  func(ctx context.Context) error { _, err := EventsByTag(2, false)(ctx); return err },
+		"MarketsByTag": func(ctx context.Context) error { _, err := MarketsByTag(2, false)(ctx); return err },
+	}
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			err := query(ctx)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected the request to be cancelled, took %v", elapsed)
+			}
+		})
+	}
+}
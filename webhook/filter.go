@@ -0,0 +1,123 @@
+// gammago/webhook/filter.go
+
+package webhook
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	gamma "github.com/Bazcampbell/gammago"
+	"github.com/Bazcampbell/gammago/watch"
+)
+
+// Filter picks the changes an endpoint receives
+// Empty fields match everything
+type Filter struct {
+	// Types limits the change types sent
+	Types []watch.ChangeType
+
+	// Tags matches if any tag's ID, slug or label is listed (case-insensitive)
+	Tags []string
+
+	// MinPriceMove drops updates where no outcome price moved by at least
+	// this much, e.g. 0.05 for five cents. Created, Closed, ActiveChanged
+	// and Removed changes always pass, as they matter without a price move
+	// For events the largest move across the event's markets is used
+	MinPriceMove float64
+
+	// MinVolume24hr drops changes to anything with less 24h volume
+	MinVolume24hr float64
+}
+
+func (f Filter) matchMarket(c watch.Change[gamma.Market]) bool {
+	m := c.New
+	if c.Type == watch.Removed {
+		m = c.Old
+	}
+	if !f.matchCommon(c.Type, m.Tags, m.Volume24hr) {
+		return false
+	}
+	if f.MinPriceMove > 0 && c.Type == watch.Updated {
+		return moved(priceMove(c.Old, c.New), f.MinPriceMove)
+	}
+	return true
+}
+
+func (f Filter) matchEvent(c watch.Change[gamma.Event]) bool {
+	e := c.New
+	if c.Type == watch.Removed {
+		e = c.Old
+	}
+	if !f.matchCommon(c.Type, e.Tags, e.Volume24hr) {
+		return false
+	}
+	if f.MinPriceMove > 0 && c.Type == watch.Updated {
+		old := make(map[string]gamma.Market, len(c.Old.Markets))
+		for _, m := range c.Old.Markets {
+			old[m.ID] = m
+		}
+		var move float64
+		for _, m := range c.New.Markets {
+			if prev, ok := old[m.ID]; ok {
+				move = max(move, priceMove(prev, m))
+			}
+		}
+		return moved(move, f.MinPriceMove)
+	}
+	return true
+}
+
+func (f Filter) matchCommon(typ watch.ChangeType, tags []gamma.Tag, volume24hr float64) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, typ) {
+		return false
+	}
+	if volume24hr < f.MinVolume24hr {
+		return false
+	}
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, t := range tags {
+		for _, want := range f.Tags {
+			if want == t.ID || strings.EqualFold(want, t.Slug) || strings.EqualFold(want, t.Label) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// priceMove is the largest absolute change across outcome prices
+func priceMove(old, new gamma.Market) float64 {
+	a, b := parsePrices(old.OutcomePrices), parsePrices(new.OutcomePrices)
+	var move float64
+	for i := range min(len(a), len(b)) {
+		move = max(move, math.Abs(b[i]-a[i]))
+	}
+	return move
+}
+
+// moved compares with a little slack so 0.47 - 0.40 counts as 0.07
+func moved(move, threshold float64) bool {
+	return move >= threshold-1e-9
+}
+
+// parsePrices decodes a JSON-encoded price list like `["0.45", "0.55"]`
+func parsePrices(s string) []float64 {
+	var list []string
+	if err := json.Unmarshal([]byte(s), &list); err != nil {
+		return nil
+	}
+	prices := make([]float64, 0, len(list))
+	for _, p := range list {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return nil
+		}
+		prices = append(prices, v)
+	}
+	return prices
+}
@@ -0,0 +1,284 @@
+// gammago/webhook/webhook.go
+
+// Package webhook POSTs watch changes to HTTP endpoints as signed JSON
+//
+// Each request carries an X-Gammago-Timestamp header and an
+// X-Gammago-Signature header of the form "sha256=<hex>", the HMAC-SHA256
+// of "<timestamp>.<body>" keyed with the endpoint's secret. Receivers can
+// check it with Verify. Deliveries that still fail after retrying are
+// appended to a dead-letter file
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+	"github.com/Bazcampbell/gammago/watch"
+)
+
+// Request headers
+const (
+	HeaderSignature = "X-Gammago-Signature"
+	HeaderTimestamp = "X-Gammago-Timestamp"
+	HeaderChange    = "X-Gammago-Change"
+)
+
+// Defaults used when the Dispatcher fields are zero
+const (
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = 500 * time.Millisecond
+)
+
+// Endpoint is one receiver and the changes it wants
+type Endpoint struct {
+	URL    string
+	Secret string
+	Filter Filter
+}
+
+// Notification is the JSON body POSTed to endpoints
+// Exactly one of Market and Event is set. It holds the new value, or the
+// old one for removals
+type Notification struct {
+	Kind   string              `json:"kind"` // "market" or "event"
+	Type   string              `json:"type"` // "created", "updated", "closed", "active_changed" or "removed"
+	ID     string              `json:"id"`
+	At     time.Time           `json:"at"`
+	Fields []watch.FieldChange `json:"fields,omitempty"`
+	Market *gamma.Market       `json:"market,omitempty"`
+	Event  *gamma.Event        `json:"event,omitempty"`
+}
+
+// DeadLetter is one line of the dead-letter file
+type DeadLetter struct {
+	URL      string          `json:"url"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	At       time.Time       `json:"at"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// Dispatcher delivers changes to every endpoint whose filter matches
+// Deliveries to different endpoints run concurrently
+type Dispatcher struct {
+	Endpoints []Endpoint
+
+	// Client sends the requests, http.DefaultClient if nil
+	Client *http.Client
+
+	// MaxAttempts and BaseDelay control retries. Network errors, 429 and
+	// 5xx responses are retried with exponential backoff; other non-2xx
+	// responses fail immediately
+	MaxAttempts int
+	BaseDelay   time.Duration
+
+	// DeadLetterPath is the file failed deliveries are appended to as JSON
+	// lines. Failures are only returned if it is empty
+	DeadLetterPath string
+
+	mu sync.Mutex // serialises dead-letter writes
+}
+
+// SendMarket delivers a market change
+func (d *Dispatcher) SendMarket(ctx context.Context, c watch.Change[gamma.Market]) error {
+	n := newNotification("market", c)
+	n.Market = &c.New
+	if c.Type == watch.Removed {
+		n.Market = &c.Old
+	}
+	return d.send(ctx, n, func(f Filter) bool { return f.matchMarket(c) })
+}
+
+// SendEvent delivers an event change
+func (d *Dispatcher) SendEvent(ctx context.Context, c watch.Change[gamma.Event]) error {
+	n := newNotification("event", c)
+	n.Event = &c.New
+	if c.Type == watch.Removed {
+		n.Event = &c.Old
+	}
+	return d.send(ctx, n, func(f Filter) bool { return f.matchEvent(c) })
+}
+
+// DispatchMarkets sends every change from ch until it closes or ctx is done
+// Delivery errors are passed to onError, which may be nil
+func (d *Dispatcher) DispatchMarkets(ctx context.Context, ch <-chan watch.Change[gamma.Market], onError func(error)) {
+	dispatch(ctx, ch, d.SendMarket, onError)
+}
+
+// DispatchEvents sends every change from ch until it closes or ctx is done
+// Delivery errors are passed to onError, which may be nil
+func (d *Dispatcher) DispatchEvents(ctx context.Context, ch <-chan watch.Change[gamma.Event], onError func(error)) {
+	dispatch(ctx, ch, d.SendEvent, onError)
+}
+
+func dispatch[T any](ctx context.Context, ch <-chan watch.Change[T], send func(context.Context, watch.Change[T]) error, onError func(error)) {
+	for {
+		select {
+		case c, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := send(ctx, c); err != nil && onError != nil {
+				onError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func newNotification[T any](kind string, c watch.Change[T]) Notification {
+	return Notification{Kind: kind, Type: c.Type.String(), ID: c.ID, At: c.At, Fields: c.Fields}
+}
+
+func (d *Dispatcher) send(ctx context.Context, n Notification, match func(Filter) bool) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(d.Endpoints))
+	for i, ep := range d.Endpoints {
+		if !match(ep.Filter) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = d.deliver(ctx, ep, n.Type, body)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// deliver POSTs body with retries, dead-lettering it on failure
+func (d *Dispatcher) deliver(ctx context.Context, ep Endpoint, changeType string, body []byte) error {
+	maxAttempts := d.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	baseDelay := d.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = DefaultBaseDelay
+	}
+
+	var lastErr error
+	attempts := 0
+	for attempt := range maxAttempts {
+		if attempt > 0 {
+			select {
+			case <-time.After(baseDelay * time.Duration(1<<(attempt-1))):
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				lastErr = err
+				break
+			}
+		}
+
+		attempts++
+		retry, err := d.post(ctx, ep, changeType, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+
+	err := fmt.Errorf("webhook %s: %w (after %d attempts)", ep.URL, lastErr, attempts)
+	if d.DeadLetterPath == "" {
+		return err
+	}
+	if dlErr := d.deadLetter(DeadLetter{
+		URL:      ep.URL,
+		Attempts: attempts,
+		Error:    lastErr.Error(),
+		At:       time.Now().UTC(),
+		Payload:  body,
+	}); dlErr != nil {
+		return errors.Join(err, dlErr)
+	}
+	return nil
+}
+
+// post makes one attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) post(ctx context.Context, ep Endpoint, changeType string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderChange, changeType)
+	if ep.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(ep.Secret, ts, body))
+	}
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}
+
+func (d *Dispatcher) deadLetter(dl DeadLetter) error {
+	line, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, err := os.OpenFile(d.DeadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Sign returns the signature header value for body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header in constant time
+func Verify(secret, timestamp, signature string, body []byte) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,250 @@
+// gammago/webhook/webhook_test.go
+
+package webhook
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+	"github.com/Bazcampbell/gammago/watch"
+)
+
+// receiver records every request it accepts
+type receiver struct {
+	mu       sync.Mutex
+	bodies   [][]byte
+	headers  []http.Header
+	failures atomic.Int32 // respond with status until this reaches 0
+	status   int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if r.failures.Add(-1) >= 0 {
+		w.WriteHeader(r.status)
+		return
+	}
+	r.mu.Lock()
+	r.bodies = append(r.bodies, body)
+	r.headers = append(r.headers, req.Header.Clone())
+	r.mu.Unlock()
+}
+
+func (r *receiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func newReceiver(t *testing.T, failures int, status int) (*receiver, *httptest.Server) {
+	r := &receiver{status: status}
+	r.failures.Store(int32(failures))
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+var priceChange = watch.Change[gamma.Market]{
+	Type: watch.Updated,
+	ID:   "501",
+	Old:  gamma.Market{ID: "501", OutcomePrices: `["0.40", "0.60"]`, Volume24hr: 5000, Tags: []gamma.Tag{{ID: "1", Slug: "sports", Label: "Sports"}}},
+	New:  gamma.Market{ID: "501", OutcomePrices: `["0.47", "0.53"]`, Volume24hr: 5000, Tags: []gamma.Tag{{ID: "1", Slug: "sports", Label: "Sports"}}},
+	Fields: []watch.FieldChange{
+		{Field: "OutcomePrices", Old: `["0.40", "0.60"]`, New: `["0.47", "0.53"]`},
+	},
+	At: time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC),
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("signed delivery", func(t *testing.T) {
+		r, srv := newReceiver(t, 0, 0)
+		d := &Dispatcher{Endpoints: []Endpoint{{URL: srv.URL, Secret: "s3cret"}}}
+
+		if err := d.SendMarket(t.Context(), priceChange); err != nil {
+			t.Fatalf("SendMarket failed: %v", err)
+		}
+		if r.received() != 1 {
+			t.Fatalf("Expected 1 delivery, got %d", r.received())
+		}
+
+		body, h := r.bodies[0], r.headers[0]
+		if !Verify("s3cret", h.Get(HeaderTimestamp), h.Get(HeaderSignature), body) {
+			t.Errorf("Signature %q did not verify", h.Get(HeaderSignature))
+		}
+		if Verify("wrong", h.Get(HeaderTimestamp), h.Get(HeaderSignature), body) {
+			t.Error("Expected signature to fail with the wrong secret")
+		}
+		if h.Get(HeaderChange) != "updated" || h.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected headers %v", h)
+		}
+
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			t.Fatalf("Bad body: %v", err)
+		}
+		if n.Kind != "market" || n.Type != "updated" || n.ID != "501" || n.Market == nil || n.Event != nil {
+			t.Errorf("Unexpected notification %+v", n)
+		}
+		if len(n.Fields) != 1 || n.Fields[0].Field != "OutcomePrices" {
+			t.Errorf("Expected field diff, got %+v", n.Fields)
+		}
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		r, srv := newReceiver(t, 2, http.StatusServiceUnavailable)
+		d := &Dispatcher{Endpoints: []Endpoint{{URL: srv.URL}}, BaseDelay: time.Millisecond}
+
+		if err := d.SendMarket(t.Context(), priceChange); err != nil {
+			t.Fatalf("SendMarket failed: %v", err)
+		}
+		if r.received() != 1 {
+			t.Errorf("Expected delivery on the third attempt, got %d", r.received())
+		}
+	})
+
+	t.Run("dead letters after the last attempt", func(t *testing.T) {
+		_, srv := newReceiver(t, 100, http.StatusInternalServerError)
+		path := filepath.Join(t.TempDir(), "dead.jsonl")
+		d := &Dispatcher{
+			Endpoints:      []Endpoint{{URL: srv.URL}},
+			MaxAttempts:    2,
+			BaseDelay:      time.Millisecond,
+			DeadLetterPath: path,
+		}
+
+		if err := d.SendMarket(t.Context(), priceChange); err != nil {
+			t.Fatalf("Expected dead-lettered failure to not error, got %v", err)
+		}
+		if err := d.SendMarket(t.Context(), priceChange); err != nil {
+			t.Fatalf("SendMarket failed: %v", err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var letters []DeadLetter
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			var dl DeadLetter
+			if err := json.Unmarshal(sc.Bytes(), &dl); err != nil {
+				t.Fatalf("Bad dead letter line: %v", err)
+			}
+			letters = append(letters, dl)
+		}
+		if len(letters) != 2 {
+			t.Fatalf("Expected 2 dead letters, got %d", len(letters))
+		}
+		if letters[0].URL != srv.URL || letters[0].Attempts != 2 || letters[0].Error != "status 500" {
+			t.Errorf("Unexpected dead letter %+v", letters[0])
+		}
+		var n Notification
+		if err := json.Unmarshal(letters[0].Payload, &n); err != nil || n.ID != "501" {
+			t.Errorf("Expected payload to be the notification, got %s", letters[0].Payload)
+		}
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		r, srv := newReceiver(t, 1, http.StatusBadRequest)
+		d := &Dispatcher{Endpoints: []Endpoint{{URL: srv.URL}}, BaseDelay: time.Millisecond}
+
+		if err := d.SendMarket(t.Context(), priceChange); err == nil {
+			t.Error("Expected error without a dead-letter file")
+		}
+		if r.received() != 0 || r.failures.Load() != 0 {
+			t.Errorf("Expected exactly one attempt")
+		}
+	})
+
+	t.Run("per-endpoint filters", func(t *testing.T) {
+		all, allSrv := newReceiver(t, 0, 0)
+		big, bigSrv := newReceiver(t, 0, 0)
+		politics, politicsSrv := newReceiver(t, 0, 0)
+		liquid, liquidSrv := newReceiver(t, 0, 0)
+		closed, closedSrv := newReceiver(t, 0, 0)
+		d := &Dispatcher{Endpoints: []Endpoint{
+			{URL: allSrv.URL},
+			{URL: bigSrv.URL, Filter: Filter{MinPriceMove: 0.1}},
+			{URL: politicsSrv.URL, Filter: Filter{Tags: []string{"politics"}}},
+			{URL: liquidSrv.URL, Filter: Filter{MinVolume24hr: 10000}},
+			{URL: closedSrv.URL, Filter: Filter{Types: []watch.ChangeType{watch.Closed}}},
+		}}
+
+		if err := d.SendMarket(t.Context(), priceChange); err != nil {
+			t.Fatalf("SendMarket failed: %v", err)
+		}
+		if all.received() != 1 || big.received() != 0 || politics.received() != 0 || liquid.received() != 0 || closed.received() != 0 {
+			t.Errorf("Only the unfiltered endpoint should match a 7c move, got %d %d %d %d %d",
+				all.received(), big.received(), politics.received(), liquid.received(), closed.received())
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Run("tags match ID, slug or label", func(t *testing.T) {
+		for _, want := range []string{"1", "SPORTS", "sports"} {
+			if !(Filter{Tags: []string{want}}).matchMarket(priceChange) {
+				t.Errorf("Expected tag %q to match", want)
+			}
+		}
+	})
+
+	t.Run("price move", func(t *testing.T) {
+		if !(Filter{MinPriceMove: 0.07}).matchMarket(priceChange) {
+			t.Error("Expected a 7c move to pass a 7c filter")
+		}
+		for _, typ := range []watch.ChangeType{watch.Created, watch.Closed, watch.ActiveChanged} {
+			c := watch.Change[gamma.Market]{Type: typ, Old: priceChange.Old, New: priceChange.New}
+			if !(Filter{MinPriceMove: 0.5}).matchMarket(c) {
+				t.Errorf("Expected price filter to pass %s changes", typ)
+			}
+		}
+	})
+
+	t.Run("event price move uses its markets", func(t *testing.T) {
+		c := watch.Change[gamma.Event]{
+			Type: watch.Updated,
+			Old:  gamma.Event{Markets: []gamma.Market{priceChange.Old, {ID: "502", OutcomePrices: `["0.1", "0.9"]`}}},
+			New:  gamma.Event{Markets: []gamma.Market{priceChange.New, {ID: "502", OutcomePrices: `["0.3", "0.7"]`}}},
+		}
+		if !(Filter{MinPriceMove: 0.15}).matchEvent(c) {
+			t.Error("Expected the 20c move on 502 to pass")
+		}
+		if (Filter{MinPriceMove: 0.25}).matchEvent(c) {
+			t.Error("Expected no market to move 25c")
+		}
+	})
+
+	t.Run("removed uses the old value", func(t *testing.T) {
+		c := watch.Change[gamma.Market]{Type: watch.Removed, Old: priceChange.Old}
+		if !(Filter{MinVolume24hr: 1000}).matchMarket(c) {
+			t.Error("Expected removal to be filtered on the old volume")
+		}
+	})
+}
+
+func TestDispatchMarkets(t *testing.T) {
+	r, srv := newReceiver(t, 0, 0)
+	d := &Dispatcher{Endpoints: []Endpoint{{URL: srv.URL}}}
+
+	ch := make(chan watch.Change[gamma.Market], 2)
+	ch <- priceChange
+	ch <- priceChange
+	close(ch)
+	d.DispatchMarkets(t.Context(), ch, func(err error) { t.Errorf("Unexpected error: %v", err) })
+
+	if r.received() != 2 {
+		t.Errorf("Expected 2 deliveries, got %d", r.received())
+	}
+}
@@ -0,0 +1,318 @@
+// gammago/ws/conn.go
+
+package ws
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// just enough of RFC 6455 for a client and the fake server: text, binary
+// and continuation frames, ping/pong and close. No extensions
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// maxMessageSize guards against a peer announcing an absurd length
+	maxMessageSize = 16 << 20
+)
+
+// CloseError is returned by reads after the peer sends a close frame
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket closed: %d %s", e.Code, e.Reason)
+}
+
+type conn struct {
+	nc     net.Conn
+	br     *bufio.Reader
+	client bool // clients mask what they send
+
+	wmu    sync.Mutex
+	closed bool
+}
+
+// dial opens a websocket to a ws:// or wss:// URL
+func dial(ctx context.Context, rawURL string) (*conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	var nc net.Conn
+	switch u.Scheme {
+	case "ws":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+		var d net.Dialer
+		nc, err = d.DialContext(ctx, "tcp", host)
+	case "wss":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+		d := tls.Dialer{Config: &tls.Config{ServerName: u.Hostname()}}
+		nc, err = d.DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("ws: unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the handshake is bounded by ctx; after that the caller owns the conn
+	stop := context.AfterFunc(ctx, func() { nc.SetDeadline(time.Unix(1, 0)) })
+	c, err := handshake(nc, u)
+	if !stop() {
+		err = errors.Join(err, ctx.Err())
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	nc.SetDeadline(time.Time{})
+	return c, nil
+}
+
+func handshake(nc net.Conn, u *url.URL) (*conn, error) {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Host:   u.Host,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+		},
+	}
+	if _, err := fmt.Fprintf(nc, "GET %s HTTP/1.1\r\nHost: %s\r\n", u.RequestURI(), u.Host); err != nil {
+		return nil, err
+	}
+	if err := req.Header.Write(nc); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(nc, "\r\n"); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("ws: handshake status %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, errors.New("ws: handshake accept key mismatch")
+	}
+	return &conn{nc: nc, br: br, client: true}, nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// upgrade turns an HTTP request into the server end of a websocket
+func upgrade(w http.ResponseWriter, r *http.Request) (*conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Header.Get("Upgrade") != "websocket" || key == "" {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return nil, errors.New("ws: not a websocket request")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking unsupported", http.StatusInternalServerError)
+		return nil, errors.New("ws: response can't be hijacked")
+	}
+	nc, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return &conn{nc: nc, br: rw.Reader}, nil
+}
+
+// readMessage reads the next text or binary message, answering pings and
+// close frames on the way
+func (c *conn) readMessage() (op int, data []byte, err error) {
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch frameOp {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			ce := &CloseError{Code: 1005}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			c.writeFrame(opClose, payload[:min(len(payload), 2)])
+			return 0, nil, ce
+		case opText, opBinary:
+			if op != 0 {
+				return 0, nil, errors.New("ws: new message inside a fragmented one")
+			}
+			op = frameOp
+		case opContinuation:
+			if op == 0 {
+				return 0, nil, errors.New("ws: continuation without a message")
+			}
+		default:
+			return 0, nil, fmt.Errorf("ws: unknown opcode %d", frameOp)
+		}
+
+		if len(data)+len(payload) > maxMessageSize {
+			return 0, nil, errors.New("ws: message too large")
+		}
+		data = append(data, payload...)
+		if fin {
+			return op, data, nil
+		}
+	}
+}
+
+func (c *conn) readFrame() (fin bool, op int, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(c.br, h[:]); err != nil {
+		return
+	}
+	fin = h[0]&0x80 != 0
+	op = int(h[0] & 0x0F)
+	masked := h[1]&0x80 != 0
+
+	n := uint64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxMessageSize {
+		err = errors.New("ws: frame too large")
+		return
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+func (c *conn) writeFrame(op int, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	if op == opClose {
+		c.closed = true
+	}
+
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|byte(op))
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+
+	if !c.client {
+		buf = append(buf, payload...)
+	} else {
+		var mask [4]byte
+		rand.Read(mask[:])
+		buf = append(buf, mask[:]...)
+		for i, b := range payload {
+			buf = append(buf, b^mask[i%4])
+		}
+	}
+	_, err := c.nc.Write(buf)
+	return err
+}
+
+func (c *conn) writeText(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// close sends a normal close frame and closes the connection without
+// waiting for the peer's reply
+func (c *conn) close() error {
+	c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, 1000))
+	return c.nc.Close()
+}
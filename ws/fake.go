@@ -0,0 +1,158 @@
+// gammago/ws/fake.go
+
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Subscription is a subscribe message received by a FakeServer
+type Subscription struct {
+	AssetIDs  []string `json:"assets_ids"`
+	Type      string   `json:"type"`
+	Operation string   `json:"operation"`
+}
+
+// FakeServer is an in-process market channel for tests. It records
+// subscriptions, answers heartbeats and sends whatever is published to
+// every connection
+type FakeServer struct {
+	// URL is the ws:// address to give a MarketSubscriber
+	URL string
+
+	srv *httptest.Server
+
+	mu      sync.Mutex
+	conns   map[*conn]struct{}
+	subs    []Subscription
+	changed chan struct{} // closed and replaced whenever subs grows
+	silent  bool
+}
+
+// NewFakeServer starts a FakeServer; Close it when done
+func NewFakeServer() *FakeServer {
+	f := &FakeServer{conns: map[*conn]struct{}{}, changed: make(chan struct{})}
+	f.srv = httptest.NewServer(http.HandlerFunc(f.serve))
+	f.URL = "ws" + strings.TrimPrefix(f.srv.URL, "http")
+	return f
+}
+
+func (f *FakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	c, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	f.mu.Lock()
+	f.conns[c] = struct{}{}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		delete(f.conns, c)
+		f.mu.Unlock()
+		c.nc.Close()
+	}()
+
+	for {
+		_, data, err := c.readMessage()
+		if err != nil {
+			return
+		}
+		if string(bytes.TrimSpace(data)) == ping {
+			f.mu.Lock()
+			silent := f.silent
+			f.mu.Unlock()
+			if !silent {
+				c.writeText([]byte(pong))
+			}
+			continue
+		}
+		var sub Subscription
+		if json.Unmarshal(data, &sub) == nil {
+			f.mu.Lock()
+			f.subs = append(f.subs, sub)
+			close(f.changed)
+			f.changed = make(chan struct{})
+			f.mu.Unlock()
+		}
+	}
+}
+
+// Publish sends v to every connection. Strings and byte slices are sent
+// as they are, anything else as JSON
+func (f *FakeServer) Publish(v any) error {
+	var data []byte
+	switch v := v.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		data = b
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for c := range f.conns {
+		c.writeText(data)
+	}
+	return nil
+}
+
+// Subscriptions returns the subscribe messages received so far
+func (f *FakeServer) Subscriptions() []Subscription {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Subscription(nil), f.subs...)
+}
+
+// WaitSubscriptions blocks until n subscribe messages have arrived in
+// total, then returns them
+func (f *FakeServer) WaitSubscriptions(ctx context.Context, n int) ([]Subscription, error) {
+	for {
+		f.mu.Lock()
+		subs, changed := f.subs, f.changed
+		f.mu.Unlock()
+		if len(subs) >= n {
+			return append([]Subscription(nil), subs...), nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// SetSilent stops (or resumes) answering heartbeats, so clients can be
+// tested for dead connection detection
+func (f *FakeServer) SetSilent(silent bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.silent = silent
+}
+
+// Drop cuts every connection without a close frame, as a network failure
+// would
+func (f *FakeServer) Drop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for c := range f.conns {
+		c.nc.Close()
+	}
+}
+
+// Close drops every connection and stops the server
+func (f *FakeServer) Close() {
+	f.Drop()
+	f.srv.Close()
+}
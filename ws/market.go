@@ -0,0 +1,380 @@
+// gammago/ws/market.go
+
+// Package ws streams Polymarket's CLOB market channel over a websocket
+//
+// A MarketSubscriber subscribes to outcome token IDs, decodes book, price
+// change, last trade and tick size messages, keeps the connection alive
+// with heartbeats and reconnects and resubscribes when it drops.
+// FakeServer is an in-process market channel for tests
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+const MarketURL = "wss://ws-subscriptions-clob.polymarket.com/ws/market"
+
+// Defaults used when the MarketSubscriber fields are 0
+const (
+	DefaultPingInterval = 10 * time.Second
+	DefaultMinBackoff   = 500 * time.Millisecond
+	DefaultMaxBackoff   = 30 * time.Second
+)
+
+// Event types of the market channel
+const (
+	EventBook           = "book"
+	EventPriceChange    = "price_change"
+	EventLastTradePrice = "last_trade_price"
+	EventTickSizeChange = "tick_size_change"
+)
+
+// heartbeat messages; the server answers PING with PONG
+const (
+	ping = "PING"
+	pong = "PONG"
+)
+
+// Level is one price level of a book
+type Level struct {
+	Price float64 `json:"price,string"`
+	Size  float64 `json:"size,string"`
+}
+
+// Book is a full snapshot of one token's book, sent on subscribe and after
+// trades
+type Book struct {
+	AssetID   string  `json:"asset_id"`
+	Market    string  `json:"market"`
+	Bids      []Level `json:"bids"`
+	Asks      []Level `json:"asks"`
+	Timestamp string  `json:"timestamp"`
+	Hash      string  `json:"hash"`
+}
+
+// PriceChange is an update to one price level of one token
+type PriceChange struct {
+	AssetID string  `json:"asset_id"`
+	Price   float64 `json:"price,string"`
+	Size    float64 `json:"size,string"`
+	Side    string  `json:"side"`
+	Hash    string  `json:"hash"`
+	BestBid string  `json:"best_bid"`
+	BestAsk string  `json:"best_ask"`
+}
+
+// PriceChanges is one price_change message, which may touch several tokens
+// of a market
+type PriceChanges struct {
+	Market    string        `json:"market"`
+	Changes   []PriceChange `json:"price_changes"`
+	Timestamp string        `json:"timestamp"`
+}
+
+// LastTrade is a trade of one token
+type LastTrade struct {
+	AssetID    string  `json:"asset_id"`
+	Market     string  `json:"market"`
+	Price      float64 `json:"price,string"`
+	Size       float64 `json:"size,string"`
+	Side       string  `json:"side"`
+	FeeRateBps string  `json:"fee_rate_bps"`
+	Timestamp  string  `json:"timestamp"`
+}
+
+// TickSizeChange is sent when a token's minimum tick changes
+type TickSizeChange struct {
+	AssetID     string `json:"asset_id"`
+	Market      string `json:"market"`
+	OldTickSize string `json:"old_tick_size"`
+	NewTickSize string `json:"new_tick_size"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// Message is one decoded event. Type is the event_type and exactly one of
+// the pointers matching it is set. Unknown types only carry Raw
+type Message struct {
+	Type        string
+	Book        *Book
+	PriceChange *PriceChanges
+	Trade       *LastTrade
+	TickSize    *TickSizeChange
+	Raw         json.RawMessage
+}
+
+// TokenIDs collects the CLOB token IDs of markets, in order
+// Markets without token IDs are skipped
+func TokenIDs(markets ...gamma.Market) []string {
+	var ids []string
+	for _, m := range markets {
+		var tokens []string
+		if json.Unmarshal([]byte(m.CLOBTokenIDs), &tokens) == nil {
+			ids = append(ids, tokens...)
+		}
+	}
+	return ids
+}
+
+// MarketSubscriber streams the market channel for a set of token IDs
+type MarketSubscriber struct {
+	// URL defaults to MarketURL
+	URL      string
+	AssetIDs []string
+
+	// PingInterval is how often a heartbeat is sent. A connection that
+	// has been silent for three intervals is treated as dead
+	PingInterval time.Duration
+
+	// reconnect delays double from MinBackoff up to MaxBackoff and reset
+	// once a connection has been established
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Buffer is the channel capacity returned by Run
+	Buffer int
+
+	// OnError is called for connection, read and decode errors; the
+	// subscriber carries on
+	OnError func(error)
+
+	mu   sync.Mutex
+	conn *conn
+}
+
+// subscription is the message sent to the market channel
+type subscription struct {
+	AssetIDs  []string `json:"assets_ids"`
+	Type      string   `json:"type,omitempty"`
+	Operation string   `json:"operation,omitempty"`
+}
+
+// Subscribe adds token IDs. They are sent on the live connection, if any,
+// and included whenever the subscriber reconnects
+func (s *MarketSubscriber) Subscribe(assetIDs ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var added []string
+	for _, id := range assetIDs {
+		if !slices.Contains(s.AssetIDs, id) {
+			s.AssetIDs = append(s.AssetIDs, id)
+			added = append(added, id)
+		}
+	}
+	if s.conn == nil || len(added) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(subscription{AssetIDs: added, Operation: "subscribe"})
+	return s.conn.writeText(b)
+}
+
+// Run connects and streams messages until ctx is cancelled, then closes
+// the channel. Messages arriving while a reconnect is in progress are lost;
+// the book snapshots sent on resubscribe bring state back in line
+func (s *MarketSubscriber) Run(ctx context.Context) <-chan Message {
+	out := make(chan Message, s.Buffer)
+	go func() {
+		defer close(out)
+
+		minBackoff, maxBackoff := s.MinBackoff, s.MaxBackoff
+		if minBackoff <= 0 {
+			minBackoff = DefaultMinBackoff
+		}
+		if maxBackoff <= 0 {
+			maxBackoff = DefaultMaxBackoff
+		}
+
+		delay := minBackoff
+		for {
+			connected, err := s.session(ctx, out)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				s.report(err)
+			}
+			if connected {
+				delay = minBackoff
+			}
+
+			t := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+			delay = min(delay*2, maxBackoff)
+		}
+	}()
+	return out
+}
+
+// session runs one connection until it fails or ctx ends
+func (s *MarketSubscriber) session(ctx context.Context, out chan<- Message) (connected bool, err error) {
+	u := s.URL
+	if u == "" {
+		u = MarketURL
+	}
+	interval := s.PingInterval
+	if interval <= 0 {
+		interval = DefaultPingInterval
+	}
+
+	c, err := dial(ctx, u)
+	if err != nil {
+		return false, err
+	}
+	defer c.close()
+
+	s.mu.Lock()
+	sub, _ := json.Marshal(subscription{AssetIDs: s.AssetIDs, Type: "market"})
+	err = c.writeText(sub)
+	if err == nil {
+		s.conn = c
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+	}()
+
+	// closing the conn unblocks the read below when ctx ends
+	stop := context.AfterFunc(ctx, func() { c.nc.Close() })
+	defer stop()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				if c.writeText([]byte(ping)) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		c.nc.SetReadDeadline(time.Now().Add(3 * interval))
+		_, data, err := c.readMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return true, nil
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				err = errors.New("ws: heartbeat timed out")
+			}
+			return true, err
+		}
+
+		data = bytes.TrimSpace(data)
+		if string(data) == pong || len(data) == 0 {
+			continue
+		}
+		msgs, err := decode(data)
+		if err != nil {
+			s.report(err)
+		}
+		for _, m := range msgs {
+			select {
+			case out <- m:
+			case <-ctx.Done():
+				return true, nil
+			}
+		}
+	}
+}
+
+func (s *MarketSubscriber) report(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+// decode splits a frame into messages; the server sends either one event
+// or an array of them
+func decode(data []byte) ([]Message, error) {
+	var raws []json.RawMessage
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, err
+		}
+	} else {
+		raws = []json.RawMessage{data}
+	}
+
+	var (
+		msgs []Message
+		errs []error
+	)
+	for _, raw := range raws {
+		m, err := decodeOne(raw)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, errors.Join(errs...)
+}
+
+func decodeOne(raw json.RawMessage) (Message, error) {
+	var head struct {
+		EventType string `json:"event_type"`
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return Message{}, err
+	}
+
+	m := Message{Type: head.EventType, Raw: raw}
+	var err error
+	switch head.EventType {
+	case EventBook:
+		// older servers call the sides buys and sells
+		var b struct {
+			Book
+			Buys  []Level `json:"buys"`
+			Sells []Level `json:"sells"`
+		}
+		err = json.Unmarshal(raw, &b)
+		if b.Bids == nil {
+			b.Bids = b.Buys
+		}
+		if b.Asks == nil {
+			b.Asks = b.Sells
+		}
+		m.Book = &b.Book
+	case EventPriceChange:
+		m.PriceChange = new(PriceChanges)
+		err = json.Unmarshal(raw, m.PriceChange)
+	case EventLastTradePrice:
+		m.Trade = new(LastTrade)
+		err = json.Unmarshal(raw, m.Trade)
+	case EventTickSizeChange:
+		m.TickSize = new(TickSizeChange)
+		err = json.Unmarshal(raw, m.TickSize)
+	}
+	if err != nil {
+		return Message{}, err
+	}
+	return m, nil
+}
@@ -0,0 +1,179 @@
+// gammago/ws/ws_test.go
+
+package ws
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gamma "github.com/Bazcampbell/gammago"
+)
+
+func TestFrames(t *testing.T) {
+	a, b := net.Pipe()
+	client := &conn{nc: a, br: bufio.NewReader(a), client: true}
+	server := &conn{nc: b, br: bufio.NewReader(b)}
+	defer client.nc.Close()
+	defer server.nc.Close()
+
+	big := bytes.Repeat([]byte("x"), 70000)
+	go func() {
+		client.writeText([]byte("hello"))
+		client.writeFrame(opBinary, big)
+	}()
+	for _, want := range [][]byte{[]byte("hello"), big} {
+		_, got, err := server.readMessage()
+		if err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("Expected %d bytes, got %d and %v", len(want), len(got), err)
+		}
+	}
+
+	// a ping is answered and a fragmented message is reassembled
+	go func() {
+		server.writeFrame(opPing, []byte("p"))
+		server.readMessage() // consumes the pong, then the close reply
+	}()
+	go func() {
+		server.nc.Write([]byte{0x01, 3, 'a', 'b', 'c'})
+		server.nc.Write([]byte{0x80, 2, 'd', 'e'})
+	}()
+	_, got, err := client.readMessage()
+	if err != nil || string(got) != "abcde" {
+		t.Fatalf("Expected abcde, got %q and %v", got, err)
+	}
+
+	go server.writeFrame(opClose, append([]byte{0x03, 0xE8}, "bye"...))
+	_, _, err = client.readMessage()
+	var ce *CloseError
+	if !errors.As(err, &ce) || ce.Code != 1000 || ce.Reason != "bye" {
+		t.Errorf("Expected close 1000 bye, got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	msgs, err := decode([]byte(`[
+		{"event_type":"book","asset_id":"a","market":"0xc1","buys":[{"price":"0.40","size":"10"}],"sells":[{"price":"0.45","size":"5"}],"timestamp":"1","hash":"h"},
+		{"event_type":"new_thing","asset_id":"a"}
+	]`))
+	if err != nil || len(msgs) != 2 {
+		t.Fatalf("Expected 2 messages, got %d and %v", len(msgs), err)
+	}
+	if b := msgs[0].Book; b == nil || b.Bids[0].Price != 0.40 || b.Asks[0].Size != 5 {
+		t.Errorf("Expected buys and sells read as bids and asks, got %+v", b)
+	}
+	if msgs[1].Type != "new_thing" || msgs[1].Book != nil || len(msgs[1].Raw) == 0 {
+		t.Errorf("Expected unknown type passed through raw, got %+v", msgs[1])
+	}
+
+	if _, err := decode([]byte(`{"event_type":"last_trade_price","price":0.5}`)); err == nil {
+		t.Error("Expected an error for a numeric price")
+	}
+}
+
+func TestTokenIDs(t *testing.T) {
+	got := TokenIDs(
+		gamma.Market{CLOBTokenIDs: `["a", "b"]`},
+		gamma.Market{},
+		gamma.Market{CLOBTokenIDs: `["c"]`},
+	)
+	if strings.Join(got, ",") != "a,b,c" {
+		t.Errorf("Expected a,b,c, got %v", got)
+	}
+}
+
+func TestMarketSubscriber(t *testing.T) {
+	srv := NewFakeServer()
+	defer srv.Close()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	s := &MarketSubscriber{
+		URL:          srv.URL,
+		AssetIDs:     TokenIDs(gamma.Market{CLOBTokenIDs: `["a", "b"]`}),
+		PingInterval: 20 * time.Millisecond,
+		MinBackoff:   5 * time.Millisecond,
+		OnError: func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		},
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	msgs := s.Run(ctx)
+
+	subs, err := srv.WaitSubscriptions(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subs[0].Type != "market" || strings.Join(subs[0].AssetIDs, ",") != "a,b" {
+		t.Errorf("Unexpected subscription %+v", subs[0])
+	}
+
+	srv.Publish(`[{"event_type":"book","asset_id":"a","market":"0xc1","bids":[{"price":"0.48","size":"100"}],"asks":[],"timestamp":"1"},
+		{"event_type":"price_change","market":"0xc1","price_changes":[{"asset_id":"a","price":"0.49","size":"20","side":"BUY","best_bid":"0.49","best_ask":"0.51"}],"timestamp":"2"}]`)
+	book, change := <-msgs, <-msgs
+	if book.Type != EventBook || book.Book.AssetID != "a" || book.Book.Bids[0].Price != 0.48 {
+		t.Errorf("Unexpected book %+v", book.Book)
+	}
+	if change.Type != EventPriceChange || change.PriceChange.Changes[0].Size != 20 {
+		t.Errorf("Unexpected price change %+v", change.PriceChange)
+	}
+
+	t.Run("subscribe while connected", func(t *testing.T) {
+		if err := s.Subscribe("b", "c"); err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+		subs, err := srv.WaitSubscriptions(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if subs[1].Operation != "subscribe" || strings.Join(subs[1].AssetIDs, ",") != "c" {
+			t.Errorf("Expected only the new ID sent, got %+v", subs[1])
+		}
+	})
+
+	t.Run("resubscribes after a drop", func(t *testing.T) {
+		srv.Drop()
+		subs, err := srv.WaitSubscriptions(ctx, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if subs[2].Type != "market" || strings.Join(subs[2].AssetIDs, ",") != "a,b,c" {
+			t.Errorf("Expected every ID on resubscribe, got %+v", subs[2])
+		}
+
+		srv.Publish(map[string]string{"event_type": "last_trade_price", "asset_id": "c", "price": "0.7", "size": "3", "side": "SELL"})
+		if m := <-msgs; m.Trade == nil || m.Trade.Price != 0.7 || m.Trade.Side != "SELL" {
+			t.Errorf("Unexpected trade %+v", m)
+		}
+	})
+
+	t.Run("silent server times out", func(t *testing.T) {
+		srv.SetSilent(true)
+		if _, err := srv.WaitSubscriptions(ctx, 4); err != nil {
+			t.Fatal(err)
+		}
+		srv.SetSilent(false)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !slices.ContainsFunc(errs, func(err error) bool { return strings.Contains(err.Error(), "heartbeat") }) {
+			t.Errorf("Expected a heartbeat error, got %v", errs)
+		}
+	})
+
+	cancel()
+	for range msgs {
+	}
+}